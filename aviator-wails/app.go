@@ -1,14 +1,21 @@
 package main
 
 import (
+	"aviator-wails/internal/audit"
+	"aviator-wails/internal/cmdline"
 	"aviator-wails/internal/config"
 	"aviator-wails/internal/discovery"
+	"aviator-wails/internal/launcher"
 	"aviator-wails/internal/processmon"
 	"aviator-wails/internal/server"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"os/exec"
+	"reflect"
+	"slices"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -61,7 +68,13 @@ func (a *App) startup(ctx context.Context) {
 	log.Println("Aviator Wails app started")
 
 	// Start background process monitoring
+	a.server.OnHealthChange(func(c server.HealthChange) {
+		runtime.EventsEmit(a.ctx, "process:health", c)
+	})
+	go a.emitProcessEvents()
 	go a.monitorProcesses()
+	go a.server.RunHealthChecks(ctx)
+	go a.server.RunScheduler(ctx)
 
 	// Auto-start HTTP Server
 	if err := a.StartServer(); err != nil {
@@ -86,6 +99,44 @@ func (a *App) monitorProcesses() {
 	}
 }
 
+// ProcessEvent is the payload of the process:started and process:stopped events
+type ProcessEvent struct {
+	AppID     string    `json:"app_id"`
+	PID       int       `json:"pid"`  // First matching PID, the last one seen for process:stopped
+	PIDs      []int     `json:"pids"` // Every matching PID (empty for process:stopped)
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// emitProcessEvents forwards process monitor transitions to the frontend
+func (a *App) emitProcessEvents() {
+	transitions, unsubscribe := a.processMonitor.Subscribe()
+	defer unsubscribe()
+
+	lastPID := make(map[string]int) // appID -> PID reported by the last process:started
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case t := <-transitions:
+			ev := ProcessEvent{AppID: t.AppID, PIDs: t.PIDs, Timestamp: t.Time}
+			if ev.PIDs == nil {
+				ev.PIDs = []int{}
+			}
+
+			name := "process:stopped"
+			if t.Running {
+				name = "process:started"
+				ev.PID = t.PIDs[0]
+				lastPID[t.AppID] = ev.PID
+			} else {
+				ev.PID = lastPID[t.AppID]
+				delete(lastPID, t.AppID)
+			}
+			runtime.EventsEmit(a.ctx, name, ev)
+		}
+	}
+}
+
 // shutdown is called when the app closes
 func (a *App) shutdown(ctx context.Context) {
 	log.Println("Shutting down Aviator")
@@ -103,26 +154,179 @@ func (a *App) GetApps() []config.App {
 }
 
 // AddApp adds a new application to the configuration
-func (a *App) AddApp(name, path, args string) config.App {
-	app := a.config.AddApp(name, path, args)
-	a.processMonitor.AddWatch(app.ID, app.Path)
+func (a *App) AddApp(name, path, args, workingDir string, env map[string]string) config.App {
+	app := a.config.AddApp(name, path, args, workingDir, env)
+	a.watchApp(app)
+	a.auditConfig("add_app", app.ID, app.Name)
 	return app
 }
 
 // UpdateApp updates an existing application
-func (a *App) UpdateApp(id, name, path, args string) bool {
-	success := a.config.UpdateApp(id, name, path, args)
+func (a *App) UpdateApp(id, name, path, args, workingDir string, env map[string]string) bool {
+	success := a.config.UpdateApp(id, name, path, args, workingDir, env)
 	if success {
 		// Update the watch with new path
-		a.processMonitor.AddWatch(id, path)
+		if app, found := a.config.GetAppByID(id); found {
+			a.watchApp(app)
+			a.auditConfig("update_app", app.ID, app.Name)
+		}
 	}
 	return success
 }
 
+// SetRestartPolicy sets how an application is relaunched after it exits
+func (a *App) SetRestartPolicy(id string, policy config.RestartPolicy) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	if app.Restart != nil && *app.Restart == policy || app.Restart == nil && policy.Mode == config.RestartNever {
+		return nil // Unchanged
+	}
+	if err := a.config.SetRestartPolicy(id, &policy); err != nil {
+		return err
+	}
+	a.auditConfig("restart_policy", app.ID, app.Name)
+	return nil
+}
+
+// SetReadiness sets how to tell an application is up after a launch.
+// A process probe without a timeout is the default and clears the setting.
+func (a *App) SetReadiness(id string, ready config.Readiness) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	var probe *config.Readiness
+	if ready != (config.Readiness{Type: config.ReadyProcess}) {
+		probe = &ready
+	}
+	if app.Ready == nil && probe == nil || app.Ready != nil && probe != nil && *app.Ready == *probe {
+		return nil // Unchanged
+	}
+	if err := a.config.SetReadiness(id, probe); err != nil {
+		return err
+	}
+	a.auditConfig("readiness", app.ID, app.Name)
+	return nil
+}
+
+// SetHealthCheck sets the periodic health check of an application, a nil check removes it
+func (a *App) SetHealthCheck(id string, check *config.HealthCheck) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	if app.Health == nil && check == nil || app.Health != nil && check != nil && *app.Health == *check {
+		return nil // Unchanged
+	}
+	if err := a.config.SetHealthCheck(id, check); err != nil {
+		return err
+	}
+	a.auditConfig("health_check", app.ID, app.Name)
+	return nil
+}
+
+// SetInstancePolicy sets whether an application may run more than once and,
+// if not, whether launching it again brings the running window to front
+func (a *App) SetInstancePolicy(id string, allowMultiple, focusExisting bool) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	if app.AllowMultipleInstances == allowMultiple && app.FocusExisting == focusExisting {
+		return nil // Unchanged
+	}
+	if err := a.config.SetInstancePolicy(id, allowMultiple, focusExisting); err != nil {
+		return err
+	}
+	a.auditConfig("instance_policy", app.ID, app.Name)
+	return nil
+}
+
+// SetKind sets whether an application is an executable, a document, a URL or a script
+func (a *App) SetKind(id, kind, interpreter string) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	if kind == launcher.KindExecutable {
+		kind = ""
+	}
+	if kind != launcher.KindScript {
+		interpreter = ""
+	}
+	if app.Kind == kind && app.Interpreter == interpreter {
+		return nil // Unchanged
+	}
+	if err := a.config.SetKind(id, kind, interpreter); err != nil {
+		return err
+	}
+	if updated, found := a.config.GetAppByID(id); found {
+		a.watchApp(updated) // Scripts are watched as their interpreter
+	}
+	a.auditConfig("kind", app.ID, app.Name)
+	return nil
+}
+
+// SetElevation sets whether an application runs elevated or as another user.
+// An empty password keeps the saved one.
+func (a *App) SetElevation(id, mode, user, password string) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	if mode == launcher.ElevationNone {
+		mode = ""
+	}
+	if app.Elevation == mode && app.RunAsUser == user && password == "" {
+		return nil // Unchanged
+	}
+	if err := a.config.SetElevation(id, mode, user, password); err != nil {
+		return err
+	}
+	a.auditConfig("elevation", app.ID, app.Name)
+	return nil
+}
+
+// SetParameters replaces the values asked for at launch, substituted for {name} in the arguments
+func (a *App) SetParameters(id string, params []config.Parameter) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	if len(app.Parameters)+len(params) == 0 || reflect.DeepEqual(app.Parameters, params) {
+		return nil // Unchanged
+	}
+	if err := a.config.SetParameters(id, params); err != nil {
+		return err
+	}
+	a.auditConfig("parameters", app.ID, app.Name)
+	return nil
+}
+
+// SetDependencies sets the applications launched before this one, refusing cycles
+func (a *App) SetDependencies(id string, dependsOn []string) error {
+	app, found := a.config.GetAppByID(id)
+	if !found {
+		return fmt.Errorf("application not found")
+	}
+	if slices.Equal(app.DependsOn, dependsOn) || len(app.DependsOn)+len(dependsOn) == 0 {
+		return nil // Unchanged
+	}
+	if err := a.config.SetDependencies(id, dependsOn); err != nil {
+		return err
+	}
+	a.auditConfig("dependencies", app.ID, app.Name)
+	return nil
+}
+
 // RemoveApp removes an application from the configuration
 func (a *App) RemoveApp(id string) {
+	app, _ := a.config.GetAppByID(id)
 	a.config.RemoveApp(id)
 	a.processMonitor.RemoveWatch(id)
+	a.auditConfig("remove_app", id, app.Name)
 }
 
 // LaunchApp launches an application by ID
@@ -136,6 +340,97 @@ func (a *App) LaunchApp(id string) error {
 	return nil
 }
 
+// StopApp closes all running processes of an application by ID,
+// killing them if they do not exit within the configured timeout
+func (a *App) StopApp(id string) error {
+	app, _ := a.config.GetAppByID(id)
+	result, err := a.server.StopApp(id, 0)
+	if !errors.Is(err, server.ErrAppNotFound) {
+		entry := audit.Entry{Action: audit.ActionStop, Source: audit.SourceDesktop, AppID: app.ID, AppName: app.Name}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		if len(result.PIDs) > 0 {
+			entry.Details = map[string]interface{}{"pids": result.PIDs, "forced": result.Forced}
+		}
+		a.server.RecordAudit(entry)
+	}
+	return err
+}
+
+// GetAuditLog returns the most recent audit log entries, oldest first
+func (a *App) GetAuditLog(limit int) ([]audit.Entry, error) {
+	return a.server.Audit.Since(time.Time{}, limit)
+}
+
+// GetSchedules returns the schedules with their next run time
+func (a *App) GetSchedules() []server.ScheduleStatus {
+	return a.server.Schedules()
+}
+
+// AddSchedule stores a new schedule for an app
+func (a *App) AddSchedule(appID, action, cron string) (config.Schedule, error) {
+	sc, err := a.config.AddSchedule(config.Schedule{AppID: appID, Action: action, Cron: cron, Enabled: true})
+	if err != nil {
+		return config.Schedule{}, err
+	}
+	app, _ := a.config.GetAppByID(appID)
+	a.auditConfig("add_schedule", app.ID, app.Name)
+	return sc, nil
+}
+
+// RemoveSchedule deletes a schedule
+func (a *App) RemoveSchedule(id string) error {
+	removed, err := a.config.RemoveSchedule(id)
+	if !removed {
+		return fmt.Errorf("schedule not found")
+	}
+	a.auditConfig("remove_schedule", "", "")
+	return err
+}
+
+// GetGroups returns the launch groups
+func (a *App) GetGroups() []config.Group {
+	return a.config.GetGroups()
+}
+
+// SaveGroup adds a launch group, or updates it if its ID exists
+func (a *App) SaveGroup(group config.Group) (config.Group, error) {
+	_, exists := a.config.GetGroupByID(group.ID)
+	saved, err := a.config.SaveGroup(group)
+	if err != nil {
+		return config.Group{}, err
+	}
+	change := "add_group"
+	if exists {
+		change = "update_group"
+	}
+	a.auditConfig(change, "", "")
+	return saved, nil
+}
+
+// RemoveGroup deletes a launch group
+func (a *App) RemoveGroup(id string) error {
+	removed, err := a.config.RemoveGroup(id)
+	if !removed {
+		return fmt.Errorf("group not found")
+	}
+	a.auditConfig("remove_group", "", "")
+	return err
+}
+
+// LaunchGroup starts the apps of a group in order and reports each step
+func (a *App) LaunchGroup(id string) ([]server.StepResult, error) {
+	group, found := a.config.GetGroupByID(id)
+	if !found {
+		return nil, fmt.Errorf("group not found")
+	}
+	return a.server.LaunchGroup(a.ctx, group, func(e audit.Entry) {
+		e.Source = audit.SourceDesktop
+		a.server.RecordAudit(e)
+	}), nil
+}
+
 // GetVersion returns the application version
 func (a *App) GetVersion() string {
 	return AppVersion
@@ -251,6 +546,11 @@ func (a *App) GetProcessStatuses() map[string]bool {
 	return a.processMonitor.GetAllStatuses()
 }
 
+// GetHealthStatuses returns the health check results of the apps that have one
+func (a *App) GetHealthStatuses() map[string]server.HealthStatus {
+	return a.server.HealthStatuses()
+}
+
 // Show makes the window visible and focused
 func (a *App) Show() {
 	if a.ctx != nil {
@@ -316,12 +616,66 @@ func (a *App) GetSettings() config.Settings {
 
 // UpdateSettings saves new settings
 func (a *App) UpdateSettings(s config.Settings) error {
-	return a.config.UpdateSettings(s)
+	if err := a.config.UpdateSettings(s); err != nil {
+		return err
+	}
+	a.auditConfig("settings", "", "")
+	return nil
 }
 
 // SetWebPIN sets a new PIN for web access
 func (a *App) SetWebPIN(pin string) error {
-	return a.config.SetWebPIN(pin)
+	if err := a.config.SetWebPIN(pin); err != nil {
+		return err
+	}
+	a.auditConfig("web_pin", "", "") // Never the PIN itself
+	return nil
+}
+
+// auditConfig records a configuration edit made from the desktop window
+func (a *App) auditConfig(change, appID, appName string) {
+	a.server.RecordAudit(audit.Entry{
+		Action:  audit.ActionConfig,
+		Source:  audit.SourceDesktop,
+		AppID:   appID,
+		AppName: appName,
+		Details: map[string]interface{}{"change": change},
+	})
+}
+
+// appWatch builds the process monitor rule for a configured app.
+// Documents and URLs run as their handler, only their Match rules can find it.
+func appWatch(app config.App) processmon.Watch {
+	w := processmon.Watch{
+		ExePath:  app.Path,
+		NameOnly: app.MatchByName,
+	}
+	if app.Kind == launcher.KindScript {
+		// The process is the interpreter, told apart from its other scripts by the command line
+		if interp, err := cmdline.Split(app.Interpreter); err == nil && len(interp) > 0 {
+			w.ExePath = interp[0]
+			if path, err := exec.LookPath(interp[0]); err == nil {
+				w.ExePath = path
+			} else {
+				w.NameOnly = true
+			}
+			w.ScriptPath = app.Path
+		}
+	}
+	if app.Match != nil {
+		w.ProcessNames = app.Match.ProcessNames
+		w.PathGlobs = app.Match.PathGlobs
+		w.CmdLineRegex = app.Match.CmdLineRegex
+		w.ChildrenOfLaunch = app.Match.ChildrenOfLaunch
+	}
+	return w
+}
+
+// watchApp (re)installs the process monitor rule for an app
+func (a *App) watchApp(app config.App) {
+	if err := a.processMonitor.AddWatch(app.ID, appWatch(app)); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 }
 
 // Helper function to get outbound IP
@@ -1,16 +1,28 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {audit} from '../models';
 import {config} from '../models';
 import {context} from '../models';
+import {server} from '../models';
 
-export function AddApp(arg1:string,arg2:string,arg3:string):Promise<config.App>;
+export function AddApp(arg1:string,arg2:string,arg3:string,arg4:string,arg5:Record<string, string>):Promise<config.App>;
+
+export function AddSchedule(arg1:string,arg2:string,arg3:string):Promise<config.Schedule>;
 
 export function GetApps():Promise<Array<config.App>>;
 
+export function GetAuditLog(arg1:number):Promise<Array<audit.Entry>>;
+
 export function GetContext():Promise<context.Context>;
 
+export function GetGroups():Promise<Array<config.Group>>;
+
+export function GetHealthStatuses():Promise<Record<string, server.HealthStatus>>;
+
 export function GetProcessStatuses():Promise<Record<string, boolean>>;
 
+export function GetSchedules():Promise<Array<server.ScheduleStatus>>;
+
 export function GetServerInfo():Promise<Record<string, any>>;
 
 export function GetSettings():Promise<config.Settings>;
@@ -25,20 +37,46 @@ export function IsWindowVisible():Promise<boolean>;
 
 export function LaunchApp(arg1:string):Promise<void>;
 
+export function LaunchGroup(arg1:string):Promise<Array<server.StepResult>>;
+
 export function RemoveApp(arg1:string):Promise<void>;
 
+export function RemoveGroup(arg1:string):Promise<void>;
+
+export function RemoveSchedule(arg1:string):Promise<void>;
+
+export function SaveGroup(arg1:config.Group):Promise<config.Group>;
+
 export function SelectFile():Promise<string>;
 
+export function SetDependencies(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetElevation(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function SetHealthCheck(arg1:string,arg2:config.HealthCheck):Promise<void>;
+
+export function SetInstancePolicy(arg1:string,arg2:boolean,arg3:boolean):Promise<void>;
+
+export function SetKind(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function SetParameters(arg1:string,arg2:Array<config.Parameter>):Promise<void>;
+
 export function SetQuitting(arg1:boolean):Promise<void>;
 
+export function SetReadiness(arg1:string,arg2:config.Readiness):Promise<void>;
+
+export function SetRestartPolicy(arg1:string,arg2:config.RestartPolicy):Promise<void>;
+
 export function SetWebPIN(arg1:string):Promise<void>;
 
 export function Show():Promise<void>;
 
 export function StartServer():Promise<void>;
 
+export function StopApp(arg1:string):Promise<void>;
+
 export function StopServer():Promise<void>;
 
-export function UpdateApp(arg1:string,arg2:string,arg3:string,arg4:string):Promise<boolean>;
+export function UpdateApp(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:Record<string, string>):Promise<boolean>;
 
 export function UpdateSettings(arg1:config.Settings):Promise<void>;
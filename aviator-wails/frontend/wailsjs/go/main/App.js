@@ -2,22 +2,42 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function AddApp(arg1, arg2, arg3) {
-  return window['go']['main']['App']['AddApp'](arg1, arg2, arg3);
+export function AddApp(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['AddApp'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function AddSchedule(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddSchedule'](arg1, arg2, arg3);
 }
 
 export function GetApps() {
   return window['go']['main']['App']['GetApps']();
 }
 
+export function GetAuditLog(arg1) {
+  return window['go']['main']['App']['GetAuditLog'](arg1);
+}
+
 export function GetContext() {
   return window['go']['main']['App']['GetContext']();
 }
 
+export function GetGroups() {
+  return window['go']['main']['App']['GetGroups']();
+}
+
+export function GetHealthStatuses() {
+  return window['go']['main']['App']['GetHealthStatuses']();
+}
+
 export function GetProcessStatuses() {
   return window['go']['main']['App']['GetProcessStatuses']();
 }
 
+export function GetSchedules() {
+  return window['go']['main']['App']['GetSchedules']();
+}
+
 export function GetServerInfo() {
   return window['go']['main']['App']['GetServerInfo']();
 }
@@ -46,18 +66,66 @@ export function LaunchApp(arg1) {
   return window['go']['main']['App']['LaunchApp'](arg1);
 }
 
+export function LaunchGroup(arg1) {
+  return window['go']['main']['App']['LaunchGroup'](arg1);
+}
+
 export function RemoveApp(arg1) {
   return window['go']['main']['App']['RemoveApp'](arg1);
 }
 
+export function RemoveGroup(arg1) {
+  return window['go']['main']['App']['RemoveGroup'](arg1);
+}
+
+export function RemoveSchedule(arg1) {
+  return window['go']['main']['App']['RemoveSchedule'](arg1);
+}
+
+export function SaveGroup(arg1) {
+  return window['go']['main']['App']['SaveGroup'](arg1);
+}
+
 export function SelectFile() {
   return window['go']['main']['App']['SelectFile']();
 }
 
+export function SetDependencies(arg1, arg2) {
+  return window['go']['main']['App']['SetDependencies'](arg1, arg2);
+}
+
+export function SetElevation(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetElevation'](arg1, arg2, arg3, arg4);
+}
+
+export function SetHealthCheck(arg1, arg2) {
+  return window['go']['main']['App']['SetHealthCheck'](arg1, arg2);
+}
+
+export function SetInstancePolicy(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetInstancePolicy'](arg1, arg2, arg3);
+}
+
+export function SetKind(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetKind'](arg1, arg2, arg3);
+}
+
+export function SetParameters(arg1, arg2) {
+  return window['go']['main']['App']['SetParameters'](arg1, arg2);
+}
+
 export function SetQuitting(arg1) {
   return window['go']['main']['App']['SetQuitting'](arg1);
 }
 
+export function SetReadiness(arg1, arg2) {
+  return window['go']['main']['App']['SetReadiness'](arg1, arg2);
+}
+
+export function SetRestartPolicy(arg1, arg2) {
+  return window['go']['main']['App']['SetRestartPolicy'](arg1, arg2);
+}
+
 export function SetWebPIN(arg1) {
   return window['go']['main']['App']['SetWebPIN'](arg1);
 }
@@ -70,12 +138,16 @@ export function StartServer() {
   return window['go']['main']['App']['StartServer']();
 }
 
+export function StopApp(arg1) {
+  return window['go']['main']['App']['StopApp'](arg1);
+}
+
 export function StopServer() {
   return window['go']['main']['App']['StopServer']();
 }
 
-export function UpdateApp(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['UpdateApp'](arg1, arg2, arg3, arg4);
+export function UpdateApp(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['UpdateApp'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
 
 export function UpdateSettings(arg1) {
@@ -1,11 +1,195 @@
+export namespace audit {
+	
+	export class Entry {
+	    // Go type: time
+	    time: any;
+	    action: string;
+	    source: string;
+	    app_id?: string;
+	    app_name?: string;
+	    client_ip?: string;
+	    session_id?: string;
+	    user_agent?: string;
+	    error?: string;
+	    details?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.action = source["action"];
+	        this.source = source["source"];
+	        this.app_id = source["app_id"];
+	        this.app_name = source["app_name"];
+	        this.client_ip = source["client_ip"];
+	        this.session_id = source["session_id"];
+	        this.user_agent = source["user_agent"];
+	        this.error = source["error"];
+	        this.details = source["details"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace config {
 	
+	export class RestartPolicy {
+	    mode: string;
+	    max_retries?: number;
+	    backoff_seconds?: number;
+	    max_backoff_seconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RestartPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.max_retries = source["max_retries"];
+	        this.backoff_seconds = source["backoff_seconds"];
+	        this.max_backoff_seconds = source["max_backoff_seconds"];
+	    }
+	}
+	export class Readiness {
+	    type: string;
+	    address?: string;
+	    url?: string;
+	    pattern?: string;
+	    timeout_seconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Readiness(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.address = source["address"];
+	        this.url = source["url"];
+	        this.pattern = source["pattern"];
+	        this.timeout_seconds = source["timeout_seconds"];
+	    }
+	}
+	export class HealthCheck {
+	    type: string;
+	    address?: string;
+	    url?: string;
+	    command?: string;
+	    interval_seconds?: number;
+	    timeout_seconds?: number;
+	    failure_threshold?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HealthCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.address = source["address"];
+	        this.url = source["url"];
+	        this.command = source["command"];
+	        this.interval_seconds = source["interval_seconds"];
+	        this.timeout_seconds = source["timeout_seconds"];
+	        this.failure_threshold = source["failure_threshold"];
+	    }
+	}
+	export class MatchRules {
+	    process_names?: string[];
+	    path_globs?: string[];
+	    cmdline_regex?: string;
+	    children_of_launch?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MatchRules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.process_names = source["process_names"];
+	        this.path_globs = source["path_globs"];
+	        this.cmdline_regex = source["cmdline_regex"];
+	        this.children_of_launch = source["children_of_launch"];
+	    }
+	}
+	export class Parameter {
+	    name: string;
+	    label?: string;
+	    type: string;
+	    default?: string;
+	    required?: boolean;
+	    options?: string[];
+	    pattern?: string;
+	    min?: number;
+	    max?: number;
+	    integer?: boolean;
+	    allow?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Parameter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.default = source["default"];
+	        this.required = source["required"];
+	        this.options = source["options"];
+	        this.pattern = source["pattern"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.integer = source["integer"];
+	        this.allow = source["allow"];
+	    }
+	}
 	export class App {
 	    id: string;
 	    name: string;
 	    path: string;
 	    args: string;
+	    arg_list?: string[];
 	    icon?: string;
+	    match_by_name?: boolean;
+	    match?: MatchRules;
+	    working_dir?: string;
+	    env?: Record<string, string>;
+	    capture_output?: boolean;
+	    restart?: RestartPolicy;
+	    allow_multiple_instances?: boolean;
+	    focus_existing?: boolean;
+	    depends_on?: string[];
+	    parameters?: Parameter[];
+	    kind?: string;
+	    interpreter?: string;
+	    elevation?: string;
+	    run_as_user?: string;
+	    run_as_password?: string;
+	    ready?: Readiness;
+	    health?: HealthCheck;
 	
 	    static createFrom(source: any = {}) {
 	        return new App(source);
@@ -17,13 +201,126 @@ export namespace config {
 	        this.name = source["name"];
 	        this.path = source["path"];
 	        this.args = source["args"];
+	        this.arg_list = source["arg_list"];
 	        this.icon = source["icon"];
+	        this.match_by_name = source["match_by_name"];
+	        this.match = this.convertValues(source["match"], MatchRules);
+	        this.working_dir = source["working_dir"];
+	        this.env = source["env"];
+	        this.capture_output = source["capture_output"];
+	        this.restart = this.convertValues(source["restart"], RestartPolicy);
+	        this.allow_multiple_instances = source["allow_multiple_instances"];
+	        this.focus_existing = source["focus_existing"];
+	        this.depends_on = source["depends_on"];
+	        this.parameters = this.convertValues(source["parameters"], Parameter);
+	        this.kind = source["kind"];
+	        this.interpreter = source["interpreter"];
+	        this.elevation = source["elevation"];
+	        this.run_as_user = source["run_as_user"];
+	        this.run_as_password = source["run_as_password"];
+	        this.ready = this.convertValues(source["ready"], Readiness);
+	        this.health = this.convertValues(source["health"], HealthCheck);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GroupStep {
+	    app_id: string;
+	    delay_seconds?: number;
+	    wait_running?: boolean;
+	    wait_timeout_seconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.app_id = source["app_id"];
+	        this.delay_seconds = source["delay_seconds"];
+	        this.wait_running = source["wait_running"];
+	        this.wait_timeout_seconds = source["wait_timeout_seconds"];
+	    }
+	}
+	export class Group {
+	    id: string;
+	    name: string;
+	    steps: GroupStep[];
+	    continue_on_error?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Group(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.steps = this.convertValues(source["steps"], GroupStep);
+	        this.continue_on_error = source["continue_on_error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Schedule {
+	    id: string;
+	    app_id: string;
+	    action: string;
+	    cron: string;
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Schedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.app_id = source["app_id"];
+	        this.action = source["action"];
+	        this.cron = source["cron"];
+	        this.enabled = source["enabled"];
 	    }
 	}
 	export class Settings {
 	    auto_start: boolean;
 	    auth_enabled: boolean;
 	    web_pin_hash: string;
+	    stop_timeout?: number;
+	    elevate_wrapper?: string;
+	    run_as_wrapper?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Settings(source);
@@ -34,8 +331,118 @@ export namespace config {
 	        this.auto_start = source["auto_start"];
 	        this.auth_enabled = source["auth_enabled"];
 	        this.web_pin_hash = source["web_pin_hash"];
+	        this.stop_timeout = source["stop_timeout"];
+	        this.elevate_wrapper = source["elevate_wrapper"];
+	        this.run_as_wrapper = source["run_as_wrapper"];
 	    }
 	}
 
 }
 
+export namespace server {
+	
+	export class HealthStatus {
+	    state: string;
+	    failures: number;
+	    error?: string;
+	    // Go type: time
+	    checked_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new HealthStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = source["state"];
+	        this.failures = source["failures"];
+	        this.error = source["error"];
+	        this.checked_at = this.convertValues(source["checked_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StepResult {
+	    app_id: string;
+	    app_name?: string;
+	    status: string;
+	    pid?: number;
+	    pids?: number[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StepResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.app_id = source["app_id"];
+	        this.app_name = source["app_name"];
+	        this.status = source["status"];
+	        this.pid = source["pid"];
+	        this.pids = source["pids"];
+	        this.error = source["error"];
+	    }
+	}
+	export class ScheduleStatus {
+	    id: string;
+	    app_id: string;
+	    action: string;
+	    cron: string;
+	    enabled: boolean;
+	    app_name: string;
+	    // Go type: time
+	    next_run?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduleStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.app_id = source["app_id"];
+	        this.action = source["action"];
+	        this.cron = source["cron"];
+	        this.enabled = source["enabled"];
+	        this.app_name = source["app_name"];
+	        this.next_run = this.convertValues(source["next_run"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionLaunch      = "launch"
+	ActionStop        = "stop"
+	ActionRestart     = "restart"
+	ActionExit        = "exit" // A launched process ended, Error is set if it crashed
+	ActionAuthSuccess = "auth_success"
+	ActionAuthFailure = "auth_failure"
+	ActionLogout      = "logout"
+	ActionConfig      = "config"
+	ActionServerStart = "server_start"
+	ActionServerStop  = "server_stop"
+)
+
+// Sources of an action
+const (
+	SourceWeb       = "web"       // HTTP API (dashboard, desktop launch button, scripts)
+	SourceDesktop   = "desktop"   // Wails window
+	SourceSystem    = "system"    // Aviator itself
+	SourceScheduler = "scheduler" // A cron schedule, Details holds its ID
+)
+
+// Entry is one line of the audit log
+type Entry struct {
+	Time      time.Time              `json:"time"`
+	Action    string                 `json:"action"`
+	Source    string                 `json:"source"`
+	AppID     string                 `json:"app_id,omitempty"`
+	AppName   string                 `json:"app_name,omitempty"`
+	ClientIP  string                 `json:"client_ip,omitempty"`
+	SessionID string                 `json:"session_id,omitempty"` // Derived from the session key, never the key itself
+	UserAgent string                 `json:"user_agent,omitempty"`
+	Error     string                 `json:"error,omitempty"` // Set when the action failed
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// Log is an append-only JSON lines file
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog returns the audit log stored at path, the file is created on the first record
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Record appends an entry, stamping it with the current time if unset
+func (l *Log) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Since returns the entries recorded after since, oldest first.
+// At most limit entries are returned, the most recent ones; 0 means no limit.
+func (l *Log) Since(since time.Time, limit int) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := []Entry{}
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Torn write, e.g. after a crash
+		}
+		if !e.Time.After(since) {
+			continue
+		}
+		entries = append(entries, e)
+		if limit > 0 && len(entries) > 2*limit {
+			entries = append(entries[:0], entries[len(entries)-limit:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
@@ -0,0 +1,139 @@
+package cmdline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split breaks a command line into arguments, shell style:
+//   - whitespace separates arguments
+//   - "double" and 'single' quotes group text containing spaces
+//   - outside quotes a backslash escapes a following quote or whitespace
+//     character
+//   - inside double quotes backslashes only escape a double quote, Windows
+//     style: 2n backslashes before a " are n backslashes and the closing
+//     quote, 2n+1 are n backslashes and a literal "
+//
+// Any other backslash is kept literally so Windows paths such as
+// C:\Program Files\App and \\server\share survive unchanged.
+func Split(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false // current holds an argument, possibly empty ("")
+	var quote rune // active quote character, 0 if none
+	escaped := false
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case escaped:
+			escaped = false
+			if r == '"' || (quote == 0 && (r == '\'' || isSpace(r))) {
+				current.WriteRune(r)
+			} else {
+				current.WriteRune('\\')
+				current.WriteRune(r)
+			}
+
+		case r == '\\' && quote == '"':
+			n := 1
+			for i+n < len(runes) && runes[i+n] == '\\' {
+				n++
+			}
+			if i+n < len(runes) && runes[i+n] == '"' {
+				current.WriteString(strings.Repeat(`\`, n/2))
+				if n%2 == 1 {
+					current.WriteRune('"')
+					n++ // Skip the escaped quote
+				}
+			} else {
+				current.WriteString(strings.Repeat(`\`, n))
+			}
+			i += n - 1
+
+		case r == '\\' && quote == 0:
+			escaped = true
+			inArg = true
+
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+
+		case isSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in arguments", quote)
+	}
+	if escaped {
+		current.WriteRune('\\') // Trailing backslash, e.g. a directory path
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// Join is the inverse of Split: arguments containing whitespace or quotes,
+// or ending in a backslash, are quoted so that Split(Join(args)) returns args.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func quote(arg string) string {
+	switch {
+	case arg == "":
+		return `""`
+	case !strings.ContainsAny(arg, " \t\r\n\"'") && !strings.HasSuffix(arg, `\`):
+		return arg
+	case !strings.Contains(arg, "'"):
+		// Single quotes are fully literal, backslashes included
+		return "'" + arg + "'"
+	}
+
+	// Backslashes before a quote, or before the closing one, are doubled
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for _, r := range arg {
+		switch r {
+		case '\\':
+			backslashes++
+		case '"':
+			b.WriteString(strings.Repeat(`\`, backslashes+1))
+			backslashes = 0
+		default:
+			backslashes = 0
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString(strings.Repeat(`\`, backslashes))
+	b.WriteByte('"')
+	return b.String()
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r' || r == '\n'
+}
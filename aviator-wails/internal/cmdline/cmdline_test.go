@@ -0,0 +1,110 @@
+package cmdline
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{``, nil},
+		{`  a  b	c `, []string{"a", "b", "c"}},
+		{`-v --name=x`, []string{"-v", "--name=x"}},
+		{`"C:\Program Files\App\app.exe" -x`, []string{`C:\Program Files\App\app.exe`, "-x"}},
+		{`\\server\share\file.txt`, []string{`\\server\share\file.txt`}},
+		{`'it''s'`, []string{"its"}},
+		{`a\ b \"c\" \'d`, []string{"a b", `"c"`, "'d"}},
+		{`'single \ keeps "all"'`, []string{`single \ keeps "all"`}},
+		{`"" ''`, []string{"", ""}},
+		{`C:\dir\`, []string{`C:\dir\`}},
+		{`"say \"hi\""`, []string{`say "hi"`}},
+		{`"C:\dir\\" next`, []string{`C:\dir\`, "next"}},
+		{`"a\\\"b"`, []string{`a\"b`}},
+		{`"\\server\share"`, []string{`\\server\share`}},
+		{`"it's C:\dir\\"`, []string{`it's C:\dir\`}},
+		{`pre"mid dle"post`, []string{"premid dlepost"}},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.in)
+		if err != nil {
+			t.Errorf("Split(%q): %v", tt.in, err)
+		} else if !slices.Equal(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`"open`, `'open`, `"C:\dir\"`} {
+		if got, err := Split(bad); err == nil {
+			t.Errorf("Split(%q) = %q, want an error", bad, got)
+		}
+	}
+}
+
+func TestJoinRoundTrip(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"plain", "-x", "--opt=1"},
+		{""},
+		{"a b", "c\td", "line\nbreak"},
+		{`C:\Program Files\App\app.exe`},
+		{`C:\dir\`, "next"},
+		{`it's C:\dir\`},
+		{`it's "quoted"`},
+		{`it's a\"b`},
+		{`it's \\"`},
+		{`say "hi"`},
+		{`\\server\share`, `\\server\share with space\`},
+		{`'`, `"`, `\`, `\\`, `'\`, `"\`},
+		{`don't`, `end\\`},
+		{"ünïcödé's path\\"},
+	}
+	for _, args := range tests {
+		joined := Join(args)
+		got, err := Split(joined)
+		if err != nil {
+			t.Errorf("Join(%q) = %s, which does not split: %v", args, joined, err)
+		} else if !slices.Equal(got, args) {
+			t.Errorf("Join(%q) = %s, splits to %q", args, joined, got)
+		}
+	}
+
+	// Every short argument made of the characters Split treats specially
+	alphabet := []string{"a", " ", "'", `"`, `\`}
+	args := []string{""}
+	for length := 1; length <= 5; length++ {
+		var longer []string
+		for _, arg := range args {
+			for _, c := range alphabet {
+				longer = append(longer, arg+c)
+			}
+		}
+		args = longer
+		for _, arg := range args {
+			joined := Join([]string{arg, arg})
+			if got, err := Split(joined); err != nil || !slices.Equal(got, []string{arg, arg}) {
+				t.Fatalf("Join(%q) = %s, splits to %q, %v", arg, joined, got, err)
+			}
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-x", "a b"}, `-x 'a b'`},
+		{[]string{`C:\dir\`}, `'C:\dir\'`},
+		{[]string{`it's C:\dir\`}, `"it's C:\dir\\"`},
+		{[]string{`it's "x"`}, `"it's \"x\""`},
+		{[]string{""}, `""`},
+	}
+	for _, tt := range tests {
+		if got := Join(tt.args); got != tt.want {
+			t.Errorf("Join(%q) = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
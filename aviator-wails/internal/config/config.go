@@ -1,10 +1,14 @@
 package config
 
 import (
+	"aviator-wails/internal/cmdline"
 	"aviator-wails/internal/icons"
+	"aviator-wails/internal/launcher"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
@@ -14,25 +18,166 @@ import (
 )
 
 type App struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Path string `json:"path"`
-	Args string `json:"args"`
-	Icon string `json:"icon,omitempty"` // Base64 encoded PNG icon
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Path        string   `json:"path"`
+	Args        string   `json:"args"`                    // Arguments as typed by the user
+	ArgList     []string `json:"arg_list,omitempty"`      // Parsed arguments, used verbatim when present
+	Icon        string   `json:"icon,omitempty"`          // Base64 encoded PNG icon
+	MatchByName bool     `json:"match_by_name,omitempty"` // Detect running state by exe filename instead of full path
+
+	WorkingDir string            `json:"working_dir,omitempty"` // Start directory, defaults to the executable's folder
+	Env        map[string]string `json:"env,omitempty"`         // Extra environment variables, values may use ${VAR}
+
+	CaptureOutput bool `json:"capture_output,omitempty"` // Save stdout/stderr to a rotating log file
+
+	Match *MatchRules `json:"match,omitempty"` // Extra rules for launchers whose workload is another process
+
+	Restart *RestartPolicy `json:"restart,omitempty"` // Relaunch the app when it exits, nil for never
+
+	AllowMultipleInstances bool `json:"allow_multiple_instances,omitempty"` // Launch even if the app is already running
+	FocusExisting          bool `json:"focus_existing,omitempty"`           // When refusing a second instance, bring the running one to front
+
+	DependsOn []string `json:"depends_on,omitempty"` // IDs of apps launched first, if not already running
+
+	Parameters []Parameter `json:"parameters,omitempty"` // Values asked at launch, substituted for {name} in the arguments
+
+	Kind        string `json:"kind,omitempty"`        // executable (default), document, url or script, see launcher.Kind*
+	Interpreter string `json:"interpreter,omitempty"` // script: interpreter command line, e.g. "python -u"
+
+	Elevation     string `json:"elevation,omitempty"`       // none (default), elevated or as-user, see launcher.Elevation*
+	RunAsUser     string `json:"run_as_user,omitempty"`     // as-user: the account to launch as
+	RunAsPassword string `json:"run_as_password,omitempty"` // as-user on Windows: protected for the current user, see protectSecret
+
+	Ready *Readiness `json:"ready,omitempty"` // How to tell the app is up after a launch, nil for when its process is seen
+
+	Health *HealthCheck `json:"health,omitempty"` // Checked periodically while running, nil for none
+}
+
+// MatchRules tells the process monitor which other processes count as the app running.
+// A process matching any rule (or the app's own executable) marks the app as running.
+type MatchRules struct {
+	ProcessNames     []string `json:"process_names,omitempty"`      // Alternate exe filenames, e.g. "game.exe"
+	PathGlobs        []string `json:"path_globs,omitempty"`         // Glob patterns on the full image path
+	CmdLineRegex     string   `json:"cmdline_regex,omitempty"`      // Regular expression on the command line
+	ChildrenOfLaunch bool     `json:"children_of_launch,omitempty"` // Any child of the process started by Aviator
+}
+
+// Restart modes
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure" // Only after an abnormal exit
+	RestartAlways    = "always"     // After any exit Aviator was not asked for
+)
+
+// RestartPolicy tells Aviator to supervise an app it launched.
+// The delay before restart N (from 0) is BackoffSeconds * 2^N, capped at MaxBackoffSeconds.
+type RestartPolicy struct {
+	Mode              string `json:"mode"`                          // never, on-failure or always
+	MaxRetries        int    `json:"max_retries,omitempty"`         // Consecutive restarts before giving up, 0 for unlimited
+	BackoffSeconds    int    `json:"backoff_seconds,omitempty"`     // First delay, defaults to 1
+	MaxBackoffSeconds int    `json:"max_backoff_seconds,omitempty"` // Delay cap, defaults to 300
+}
+
+// Validate checks the mode and limits of a restart policy
+func (p RestartPolicy) Validate() error {
+	switch p.Mode {
+	case RestartNever, RestartOnFailure, RestartAlways:
+	default:
+		return fmt.Errorf("invalid restart mode %q (expected never, on-failure or always)", p.Mode)
+	}
+	if p.MaxRetries < 0 || p.BackoffSeconds < 0 || p.MaxBackoffSeconds < 0 {
+		return fmt.Errorf("restart retries and delays cannot be negative")
+	}
+	return nil
+}
+
+// Arguments returns the argument vector to launch the app with:
+// ArgList if set, otherwise Args split with shell-style quoting
+func (a App) Arguments() ([]string, error) {
+	if len(a.ArgList) > 0 {
+		return a.ArgList, nil
+	}
+	return cmdline.Split(a.Args)
+}
+
+// OpenedByHandler reports whether the app is a document or URL: the process
+// Aviator starts only hands it to its default application and exits
+func (a App) OpenedByHandler() bool {
+	return a.Kind == launcher.KindDocument || a.Kind == launcher.KindURL
+}
+
+// syncArgs fills ArgList from Args and vice versa, whichever is missing.
+// It reports whether the app was changed.
+func (a *App) syncArgs() bool {
+	switch {
+	case len(a.ArgList) == 0 && a.Args != "":
+		args, err := cmdline.Split(a.Args)
+		if err != nil {
+			log.Printf("Warning: Could not parse arguments of %s: %v", a.Name, err)
+			return false
+		}
+		a.ArgList = args
+		return len(args) > 0
+	case a.Args == "" && len(a.ArgList) > 0:
+		a.Args = cmdline.Join(a.ArgList)
+		return true
+	}
+	return false
 }
 
 type Settings struct {
 	AutoStart   bool   `json:"auto_start"`
 	AuthEnabled bool   `json:"auth_enabled"`
 	WebPINHash  string `json:"web_pin_hash"`
+	StopTimeout int    `json:"stop_timeout,omitempty"` // Seconds to wait for a graceful close before killing (0 = default)
+
+	// Outside Windows: commands put in front of elevated and as-user launches,
+	// e.g. "pkexec" or "pkexec --user {user}" (empty = sudo -n)
+	ElevateWrapper string `json:"elevate_wrapper,omitempty"`
+	RunAsWrapper   string `json:"run_as_wrapper,omitempty"`
 }
 
 type ConfigManager struct {
-	Apps         []App
-	Settings     Settings
-	FilePath     string // config.json (apps)
-	SettingsPath string // settings.json (preferences)
-	mu           sync.RWMutex
+	Apps          []App
+	Settings      Settings
+	Schedules     []Schedule
+	Groups        []Group
+	FilePath      string // config.json (apps)
+	SettingsPath  string // settings.json (preferences)
+	SchedulesPath string // schedules.json (timed launches and stops)
+	GroupsPath    string // groups.json (apps launched in sequence)
+	LogDir        string // logs/ (captured app output)
+	AuditPath     string // audit.jsonl (who launched what and when)
+	mu            sync.RWMutex
+
+	listeners []func(kind string) // Called after every save, see OnChange
+}
+
+// Kinds of change passed to OnChange listeners
+const (
+	ChangeApps      = "apps"
+	ChangeSettings  = "settings"
+	ChangeSchedules = "schedules"
+	ChangeGroups    = "groups"
+)
+
+// OnChange registers fn to be called after the apps, settings, schedules or groups are saved
+func (cm *ConfigManager) OnChange(fn func(kind string)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.listeners = append(cm.listeners, fn)
+}
+
+// notify calls the change listeners, without holding the lock so they may read the config
+func (cm *ConfigManager) notify(kind string) {
+	cm.mu.RLock()
+	listeners := append([]func(string){}, cm.listeners...)
+	cm.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(kind)
+	}
 }
 
 func NewConfigManager() (*ConfigManager, error) {
@@ -56,17 +201,68 @@ func NewConfigManager() (*ConfigManager, error) {
 	}
 
 	cm := &ConfigManager{
-		Apps:         []App{},
-		Settings:     Settings{AutoStart: false},
-		FilePath:     filepath.Join(aviatorDir, "config.json"),
-		SettingsPath: filepath.Join(aviatorDir, "settings.json"),
+		Apps:          []App{},
+		Settings:      Settings{AutoStart: false},
+		FilePath:      filepath.Join(aviatorDir, "config.json"),
+		SettingsPath:  filepath.Join(aviatorDir, "settings.json"),
+		SchedulesPath: filepath.Join(aviatorDir, "schedules.json"),
+		GroupsPath:    filepath.Join(aviatorDir, "groups.json"),
+		LogDir:        filepath.Join(aviatorDir, "logs"),
+		AuditPath:     filepath.Join(aviatorDir, "audit.jsonl"),
 	}
 
 	cm.Load()         // Ignore error on load apps
 	cm.LoadSettings() // Ignore error on load settings
+	if err := cm.LoadSchedules(); err != nil {
+		log.Printf("Warning: Could not load schedules: %v", err)
+	}
+	if err := cm.LoadGroups(); err != nil {
+		log.Printf("Warning: Could not load groups: %v", err)
+	}
+
+	if err := checkDependencies(cm.Apps); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	for _, app := range cm.Apps {
+		if err := validateParameters(app.Parameters); err != nil {
+			log.Printf("Warning: %s: %v", app.Name, err)
+		}
+		if err := launcher.ValidateElevation(app.Elevation, app.RunAsUser); err != nil {
+			log.Printf("Warning: %s: %v", app.Name, err)
+		}
+		if app.Ready != nil {
+			if err := app.Ready.Validate(); err != nil {
+				log.Printf("Warning: %s: readiness: %v", app.Name, err)
+			}
+		}
+		if app.Health != nil {
+			if err := app.Health.Validate(); err != nil {
+				log.Printf("Warning: %s: health check: %v", app.Name, err)
+			}
+		}
+	}
+
+	if cm.migrate() {
+		cm.Save()
+	}
 	return cm, nil
 }
 
+// migrate upgrades apps saved by older versions, reporting whether anything changed
+func (cm *ConfigManager) migrate() bool {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	changed := false
+	for i := range cm.Apps {
+		// Args used to be split on whitespace at launch time
+		if cm.Apps[i].syncArgs() {
+			changed = true
+		}
+	}
+	return changed
+}
+
 func (cm *ConfigManager) Load() error {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
@@ -85,17 +281,20 @@ func (cm *ConfigManager) Load() error {
 
 func (cm *ConfigManager) Save() error {
 	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-
 	data, err := json.MarshalIndent(cm.Apps, "", "    ")
+	cm.mu.RUnlock()
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(cm.FilePath, data, 0644)
+	if err := os.WriteFile(cm.FilePath, data, 0644); err != nil {
+		return err
+	}
+	cm.notify(ChangeApps)
+	return nil
 }
 
-func (cm *ConfigManager) AddApp(name, path, args string) App {
+func (cm *ConfigManager) AddApp(name, path, args, workingDir string, env map[string]string) App {
 	cm.mu.Lock()
 
 	// Extract icon from executable
@@ -111,7 +310,11 @@ func (cm *ConfigManager) AddApp(name, path, args string) App {
 		Path: path,
 		Args: args,
 		Icon: iconBase64,
+
+		WorkingDir: workingDir,
+		Env:        env,
 	}
+	app.syncArgs()
 	cm.Apps = append(cm.Apps, app)
 	cm.mu.Unlock()
 
@@ -119,7 +322,7 @@ func (cm *ConfigManager) AddApp(name, path, args string) App {
 	return app
 }
 
-func (cm *ConfigManager) UpdateApp(id, name, path, args string) bool {
+func (cm *ConfigManager) UpdateApp(id, name, path, args, workingDir string, env map[string]string) bool {
 	cm.mu.Lock()
 	found := false
 	for i, app := range cm.Apps {
@@ -135,7 +338,14 @@ func (cm *ConfigManager) UpdateApp(id, name, path, args string) bool {
 			}
 			cm.Apps[i].Name = name
 			cm.Apps[i].Path = path
-			cm.Apps[i].Args = args
+			if app.Args != args {
+				// Re-parse the edited string, keeping ArgList otherwise
+				cm.Apps[i].Args = args
+				cm.Apps[i].ArgList = nil
+				cm.Apps[i].syncArgs()
+			}
+			cm.Apps[i].WorkingDir = workingDir
+			cm.Apps[i].Env = env
 			found = true
 			break
 		}
@@ -148,18 +358,179 @@ func (cm *ConfigManager) UpdateApp(id, name, path, args string) bool {
 	return found
 }
 
+// SetRestartPolicy replaces the restart policy of an app, nil disables restarts
+func (cm *ConfigManager) SetRestartPolicy(id string, policy *RestartPolicy) error {
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		if policy.Mode == RestartNever {
+			policy = nil
+		}
+	}
+
+	cm.mu.Lock()
+	found := false
+	for i := range cm.Apps {
+		if cm.Apps[i].ID == id {
+			if policy != nil && cm.Apps[i].OpenedByHandler() {
+				cm.mu.Unlock()
+				return errNoRestartForHandler // The opener exits at once, it would be reopened forever
+			}
+			cm.Apps[i].Restart = policy
+			found = true
+			break
+		}
+	}
+	cm.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("app not found: %s", id)
+	}
+	return cm.Save()
+}
+
+var errNoRestartForHandler = errors.New("documents and URLs cannot be restarted automatically")
+
+// SetKind sets what the app's path is and how it is started
+func (cm *ConfigManager) SetKind(id, kind, interpreter string) error {
+	if kind == launcher.KindExecutable {
+		kind = "" // The default, kept out of config.json
+	}
+	if kind != launcher.KindScript {
+		interpreter = ""
+	}
+
+	cm.mu.Lock()
+	found := false
+	for i := range cm.Apps {
+		if cm.Apps[i].ID == id {
+			args, err := cm.Apps[i].Arguments()
+			if err == nil {
+				err = launcher.ValidateTarget(kind, cm.Apps[i].Path, interpreter, args)
+			}
+			if err == nil && cm.Apps[i].Restart != nil && (App{Kind: kind}).OpenedByHandler() {
+				err = errNoRestartForHandler
+			}
+			if err != nil {
+				cm.mu.Unlock()
+				return err
+			}
+			cm.Apps[i].Kind = kind
+			cm.Apps[i].Interpreter = interpreter
+			found = true
+			break
+		}
+	}
+	cm.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("app not found: %s", id)
+	}
+	return cm.Save()
+}
+
+// SetParameters replaces the launch parameters of an app
+func (cm *ConfigManager) SetParameters(id string, params []Parameter) error {
+	if err := validateParameters(params); err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	found := false
+	for i := range cm.Apps {
+		if cm.Apps[i].ID == id {
+			cm.Apps[i].Parameters = params
+			found = true
+			break
+		}
+	}
+	cm.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("app not found: %s", id)
+	}
+	return cm.Save()
+}
+
+// SetInstancePolicy sets whether an app may run more than once and, if not,
+// whether a refused launch brings the running instance to front
+func (cm *ConfigManager) SetInstancePolicy(id string, allowMultiple, focusExisting bool) error {
+	cm.mu.Lock()
+	found := false
+	for i := range cm.Apps {
+		if cm.Apps[i].ID == id {
+			cm.Apps[i].AllowMultipleInstances = allowMultiple
+			cm.Apps[i].FocusExisting = focusExisting
+			found = true
+			break
+		}
+	}
+	cm.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("app not found: %s", id)
+	}
+	return cm.Save()
+}
+
 func (cm *ConfigManager) RemoveApp(id string) {
 	cm.mu.Lock()
 	newApps := []App{}
 	for _, app := range cm.Apps {
-		if app.ID != id {
-			newApps = append(newApps, app)
+		if app.ID == id {
+			continue
 		}
+		// Apps that needed the removed one no longer wait for it
+		var deps []string
+		for _, dep := range app.DependsOn {
+			if dep != id {
+				deps = append(deps, dep)
+			}
+		}
+		app.DependsOn = deps
+		newApps = append(newApps, app)
 	}
 	cm.Apps = newApps
+
+	// Drop the schedules of the removed app
+	schedules := []Schedule{}
+	for _, s := range cm.Schedules {
+		if s.AppID != id {
+			schedules = append(schedules, s)
+		}
+	}
+	schedulesChanged := len(schedules) != len(cm.Schedules)
+	cm.Schedules = schedules
+
+	// And its steps in launch groups, groups left empty go too
+	groupsChanged := false
+	groups := []Group{}
+	for _, g := range cm.Groups {
+		steps := []GroupStep{}
+		for _, step := range g.Steps {
+			if step.AppID != id {
+				steps = append(steps, step)
+			}
+		}
+		if len(steps) != len(g.Steps) {
+			groupsChanged = true
+			g.Steps = steps
+		}
+		if len(steps) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	cm.Groups = groups
 	cm.mu.Unlock()
 
 	cm.Save()
+	if schedulesChanged {
+		cm.SaveSchedules()
+	}
+	if groupsChanged {
+		cm.SaveGroups()
+	}
 }
 
 func (cm *ConfigManager) GetApps() []App {
@@ -206,14 +577,17 @@ func (cm *ConfigManager) LoadSettings() error {
 
 func (cm *ConfigManager) SaveSettings() error {
 	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-
 	data, err := json.MarshalIndent(cm.Settings, "", "    ")
+	cm.mu.RUnlock()
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(cm.SettingsPath, data, 0644)
+	if err := os.WriteFile(cm.SettingsPath, data, 0644); err != nil {
+		return err
+	}
+	cm.notify(ChangeSettings)
+	return nil
 }
 
 func (cm *ConfigManager) GetSettings() Settings {
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"aviator-wails/internal/launcher"
+	"path/filepath"
+	"testing"
+)
+
+// testManager is a config manager saving to a temp dir
+func testManager(t *testing.T, apps ...App) *ConfigManager {
+	t.Helper()
+	return &ConfigManager{Apps: apps, FilePath: filepath.Join(t.TempDir(), "config.json")}
+}
+
+func TestRestartPolicyOfHandlerKinds(t *testing.T) {
+	cm := testManager(t,
+		App{ID: "doc", Kind: launcher.KindDocument},
+		App{ID: "url", Kind: launcher.KindURL},
+		App{ID: "exe", Path: "/usr/bin/true"},
+	)
+	always := &RestartPolicy{Mode: RestartAlways}
+
+	for _, id := range []string{"doc", "url"} {
+		if err := cm.SetRestartPolicy(id, always); err == nil {
+			t.Errorf("%s: restart policy accepted", id)
+		}
+		if err := cm.SetRestartPolicy(id, &RestartPolicy{Mode: RestartNever}); err != nil {
+			t.Errorf("%s: never refused: %v", id, err)
+		}
+	}
+
+	if err := cm.SetRestartPolicy("exe", always); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.SetKind("exe", launcher.KindURL, ""); err == nil {
+		t.Error("kind changed to url while restarting always")
+	}
+	if err := cm.SetRestartPolicy("exe", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.SetKind("exe", launcher.KindDocument, ""); err != nil {
+		t.Errorf("kind change without a restart policy: %v", err)
+	}
+}
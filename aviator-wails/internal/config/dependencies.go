@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkDependencies reports dependencies on unknown apps and cycles such as A → B → A
+func checkDependencies(apps []App) error {
+	byID := make(map[string]App, len(apps))
+	for _, app := range apps {
+		byID[app.ID] = app
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(apps))
+	var path []App // Apps on the current walk, to describe a cycle
+
+	var visit func(app App) error
+	visit = func(app App) error {
+		switch state[app.ID] {
+		case done:
+			return nil
+		case visiting:
+			var names []string
+			for i := len(path) - 1; i >= 0; i-- {
+				names = append([]string{path[i].Name}, names...)
+				if path[i].ID == app.ID {
+					break
+				}
+			}
+			return fmt.Errorf("dependency cycle: %s → %s", strings.Join(names, " → "), app.Name)
+		}
+
+		state[app.ID] = visiting
+		path = append(path, app)
+		for _, depID := range app.DependsOn {
+			dep, ok := byID[depID]
+			if !ok {
+				return fmt.Errorf("%s depends on an unknown app: %s", app.Name, depID)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[app.ID] = done
+		return nil
+	}
+
+	for _, app := range apps {
+		if err := visit(app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetDependencies replaces the apps an app needs running before it is launched,
+// refusing unknown apps and cycles
+func (cm *ConfigManager) SetDependencies(id string, dependsOn []string) error {
+	cm.mu.Lock()
+	apps := make([]App, len(cm.Apps))
+	copy(apps, cm.Apps)
+	found := false
+	for i := range apps {
+		if apps[i].ID == id {
+			apps[i].DependsOn = dependsOn
+			found = true
+			break
+		}
+	}
+	if !found {
+		cm.mu.Unlock()
+		return fmt.Errorf("app not found: %s", id)
+	}
+	if err := checkDependencies(apps); err != nil {
+		cm.mu.Unlock()
+		return err
+	}
+	cm.Apps = apps
+	cm.mu.Unlock()
+
+	return cm.Save()
+}
+
+// DependencyOrder returns the apps an app depends on, directly or not,
+// in the order they have to be started
+func (cm *ConfigManager) DependencyOrder(id string) ([]App, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	byID := make(map[string]App, len(cm.Apps))
+	for _, app := range cm.Apps {
+		byID[app.ID] = app
+	}
+	root, ok := byID[id]
+	if !ok {
+		return nil, fmt.Errorf("app not found: %s", id)
+	}
+
+	var order []App
+	added := make(map[string]bool)
+	onPath := make(map[string]bool) // A hand-edited config may still hold a cycle
+	var visit func(app App) error
+	visit = func(app App) error {
+		if onPath[app.ID] {
+			return fmt.Errorf("dependency cycle through %s", app.Name)
+		}
+		onPath[app.ID] = true
+		for _, depID := range app.DependsOn {
+			dep, ok := byID[depID]
+			if !ok {
+				return fmt.Errorf("%s depends on an unknown app: %s", app.Name, depID)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+			if !added[dep.ID] {
+				added[dep.ID] = true
+				order = append(order, dep)
+			}
+		}
+		onPath[app.ID] = false
+		return nil
+	}
+
+	if err := visit(root); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
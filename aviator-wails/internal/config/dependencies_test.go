@@ -0,0 +1,92 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// dependent is an app with the given dependencies, named after its ID
+func dependent(id string, dependsOn ...string) App {
+	return App{ID: id, Name: id, DependsOn: dependsOn}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	tests := []struct {
+		name string
+		apps []App
+		err  string // Empty for valid
+	}{
+		{"none", []App{dependent("A"), dependent("B")}, ""},
+		{"chain", []App{dependent("A", "B"), dependent("B", "C"), dependent("C")}, ""},
+		{"diamond", []App{dependent("A", "B", "C"), dependent("B", "D"), dependent("C", "D"), dependent("D")}, ""},
+		{"self", []App{dependent("A", "A")}, "dependency cycle: A → A"},
+		{"two apps", []App{dependent("A", "B"), dependent("B", "A")}, "dependency cycle: A → B → A"},
+		{"behind a chain", []App{dependent("X", "A"), dependent("A", "B"), dependent("B", "C"), dependent("C", "A")}, "dependency cycle: A → B → C → A"},
+		{"unknown", []App{dependent("A", "B"), dependent("B", "missing")}, "B depends on an unknown app: missing"},
+	}
+	for _, tt := range tests {
+		err := checkDependencies(tt.apps)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.err != "" && (err == nil || err.Error() != tt.err):
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestSetDependencies(t *testing.T) {
+	cm := testManager(t, dependent("A", "B"), dependent("B"))
+
+	for _, deps := range [][]string{{"A"}, {"B"}, {"missing"}} {
+		if err := cm.SetDependencies("B", deps); err == nil {
+			t.Errorf("B depending on %v accepted", deps)
+		}
+	}
+	if app, _ := cm.GetAppByID("B"); len(app.DependsOn) != 0 {
+		t.Errorf("refused change was kept: %v", app.DependsOn)
+	}
+	if err := cm.SetDependencies("missing", nil); err == nil {
+		t.Error("unknown app accepted")
+	}
+}
+
+func TestDependencyOrder(t *testing.T) {
+	ids := func(apps []App) []string {
+		var ids []string
+		for _, app := range apps {
+			ids = append(ids, app.ID)
+		}
+		return ids
+	}
+
+	// D is needed by both B and C: it starts once, before either
+	cm := testManager(t, dependent("A", "B", "C"), dependent("B", "D"), dependent("C", "D"), dependent("D"))
+	order, err := cm.DependencyOrder("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(order); !slices.Equal(got, []string{"D", "B", "C"}) {
+		t.Errorf("diamond: got %v, want [D B C]", got)
+	}
+	if order, _ := cm.DependencyOrder("D"); len(order) != 0 {
+		t.Errorf("no dependencies: got %v", ids(order))
+	}
+
+	// A hand-edited config skips checkDependencies
+	for name, apps := range map[string][]App{
+		"self":    {dependent("A", "A")},
+		"cycle":   {dependent("A", "B"), dependent("B", "A")},
+		"unknown": {dependent("A", "missing")},
+	} {
+		cm := testManager(t, apps...)
+		if order, err := cm.DependencyOrder("A"); err == nil {
+			t.Errorf("%s: got %v, want an error", name, ids(order))
+		}
+	}
+
+	if _, err := cm.DependencyOrder("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unknown app: %v", err)
+	}
+}
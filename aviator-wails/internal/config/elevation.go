@@ -0,0 +1,62 @@
+package config
+
+import (
+	"aviator-wails/internal/launcher"
+	"fmt"
+)
+
+// SetElevation sets the rights an app is launched with. For as-user an empty
+// password keeps the one saved for the same account.
+func (cm *ConfigManager) SetElevation(id, mode, user, password string) error {
+	if err := launcher.ValidateElevation(mode, user); err != nil {
+		return err
+	}
+	if mode == launcher.ElevationNone {
+		mode = "" // The default, kept out of config.json
+	}
+	if mode != launcher.ElevationAsUser {
+		user, password = "", ""
+	}
+	sealed, err := protectSecret(password)
+	if err != nil {
+		return fmt.Errorf("cannot store password: %w", err)
+	}
+
+	cm.mu.Lock()
+	found := false
+	for i := range cm.Apps {
+		if cm.Apps[i].ID == id {
+			if password == "" && user != "" && user == cm.Apps[i].RunAsUser {
+				sealed = cm.Apps[i].RunAsPassword
+			}
+			cm.Apps[i].Elevation = mode
+			cm.Apps[i].RunAsUser = user
+			cm.Apps[i].RunAsPassword = sealed
+			found = true
+			break
+		}
+	}
+	cm.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("app not found: %s", id)
+	}
+	return cm.Save()
+}
+
+// LaunchElevation returns what the launcher needs to start an app with its
+// elevation, using the wrappers in settings where the platform relies on one
+func (a App) LaunchElevation(settings Settings) (launcher.Elevation, error) {
+	e := launcher.Elevation{Mode: a.Elevation}
+	switch a.Elevation {
+	case launcher.ElevationElevated:
+		e.Wrapper = settings.ElevateWrapper
+	case launcher.ElevationAsUser:
+		password, err := unprotectSecret(a.RunAsPassword)
+		if err != nil {
+			return e, fmt.Errorf("cannot read the password of %s: %w", a.RunAsUser, err)
+		}
+		e.User, e.Password, e.Wrapper = a.RunAsUser, password, settings.RunAsWrapper
+	}
+	return e, nil
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DefaultWaitTimeout is how long to wait for a launched app to run, or be ready, when no timeout is set
+const DefaultWaitTimeout = 30
+
+// GroupStep is one app of a launch group
+type GroupStep struct {
+	AppID              string `json:"app_id"`
+	DelaySeconds       int    `json:"delay_seconds,omitempty"`        // Pause before launching this step
+	WaitRunning        bool   `json:"wait_running,omitempty"`         // Wait for the app to be ready (see App.Ready) before the next step
+	WaitTimeoutSeconds int    `json:"wait_timeout_seconds,omitempty"` // Give up waiting after this long, defaults to the app's readiness timeout
+}
+
+// Group launches several apps in order, e.g. a streaming setup
+type Group struct {
+	ID              string      `json:"id"`
+	Name            string      `json:"name"`
+	Steps           []GroupStep `json:"steps"`
+	ContinueOnError bool        `json:"continue_on_error,omitempty"` // Run the remaining steps after one fails
+}
+
+// validateGroup checks the name and steps of a group, the caller holds the lock
+func (cm *ConfigManager) validateGroup(g Group) error {
+	if strings.TrimSpace(g.Name) == "" {
+		return fmt.Errorf("group name is required")
+	}
+	if len(g.Steps) == 0 {
+		return fmt.Errorf("group needs at least one app")
+	}
+	for i, step := range g.Steps {
+		if !cm.hasApp(step.AppID) {
+			return fmt.Errorf("step %d: app not found: %s", i+1, step.AppID)
+		}
+		if step.DelaySeconds < 0 || step.WaitTimeoutSeconds < 0 {
+			return fmt.Errorf("step %d: delays cannot be negative", i+1)
+		}
+	}
+	return nil
+}
+
+// hasApp reports whether an app exists, the caller holds the lock
+func (cm *ConfigManager) hasApp(id string) bool {
+	for _, app := range cm.Apps {
+		if app.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (cm *ConfigManager) LoadGroups() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	data, err := os.ReadFile(cm.GroupsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			cm.Groups = []Group{}
+			return nil
+		}
+		return err
+	}
+
+	return json.Unmarshal(data, &cm.Groups)
+}
+
+func (cm *ConfigManager) SaveGroups() error {
+	cm.mu.RLock()
+	data, err := json.MarshalIndent(cm.Groups, "", "    ")
+	cm.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(cm.GroupsPath, data, 0644); err != nil {
+		return err
+	}
+	cm.notify(ChangeGroups)
+	return nil
+}
+
+func (cm *ConfigManager) GetGroups() []Group {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	groups := make([]Group, len(cm.Groups))
+	copy(groups, cm.Groups)
+	return groups
+}
+
+func (cm *ConfigManager) GetGroupByID(id string) (Group, bool) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	for _, g := range cm.Groups {
+		if g.ID == id {
+			return g, true
+		}
+	}
+	return Group{}, false
+}
+
+// SaveGroup validates and stores a group, adding it if its ID is empty or unknown
+func (cm *ConfigManager) SaveGroup(g Group) (Group, error) {
+	cm.mu.Lock()
+	if err := cm.validateGroup(g); err != nil {
+		cm.mu.Unlock()
+		return Group{}, err
+	}
+
+	updated := false
+	for i := range cm.Groups {
+		if g.ID != "" && cm.Groups[i].ID == g.ID {
+			cm.Groups[i] = g
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		if g.ID == "" {
+			g.ID = uuid.New().String()
+		}
+		cm.Groups = append(cm.Groups, g)
+	}
+	cm.mu.Unlock()
+
+	return g, cm.SaveGroups()
+}
+
+// RemoveGroup deletes a group, reporting whether it existed
+func (cm *ConfigManager) RemoveGroup(id string) (bool, error) {
+	cm.mu.Lock()
+	found := false
+	kept := []Group{}
+	for _, g := range cm.Groups {
+		if g.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, g)
+	}
+	cm.Groups = kept
+	cm.mu.Unlock()
+
+	if !found {
+		return false, nil
+	}
+	return true, cm.SaveGroups()
+}
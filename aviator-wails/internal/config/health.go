@@ -0,0 +1,110 @@
+package config
+
+import (
+	"aviator-wails/internal/cmdline"
+	"fmt"
+	"time"
+)
+
+// Health check types
+const (
+	HealthTCP     = "tcp"     // Address accepts connections
+	HealthHTTP    = "http"    // A GET of URL answers 2xx
+	HealthCommand = "command" // Command exits with code 0
+)
+
+// Defaults of a health check, in seconds and checks
+const (
+	DefaultHealthInterval = 30
+	DefaultHealthTimeout  = 5
+	DefaultHealthFailures = 3
+)
+
+// HealthCheck is run periodically while the app is running to tell whether it
+// actually works, not only whether its process exists
+type HealthCheck struct {
+	Type             string `json:"type"`
+	Address          string `json:"address,omitempty"` // tcp: host:port
+	URL              string `json:"url,omitempty"`     // http
+	Command          string `json:"command,omitempty"` // command: command line, run without a shell, may use ${VAR}
+	IntervalSeconds  int    `json:"interval_seconds,omitempty"`
+	TimeoutSeconds   int    `json:"timeout_seconds,omitempty"`
+	FailureThreshold int    `json:"failure_threshold,omitempty"` // Consecutive failures before the app is unhealthy
+}
+
+// Validate checks a health check
+func (h HealthCheck) Validate() error {
+	switch h.Type {
+	case HealthTCP:
+		if err := validateAddress(h.Address); err != nil {
+			return err
+		}
+	case HealthHTTP:
+		if err := validateHTTPURL(h.URL); err != nil {
+			return err
+		}
+	case HealthCommand:
+		words, err := cmdline.Split(h.Command)
+		if err != nil {
+			return fmt.Errorf("invalid command: %w", err)
+		}
+		if len(words) == 0 {
+			return fmt.Errorf("command health check needs a command")
+		}
+	default:
+		return fmt.Errorf("invalid health check type %q (expected tcp, http or command)", h.Type)
+	}
+	if h.IntervalSeconds < 0 || h.TimeoutSeconds < 0 || h.FailureThreshold < 0 {
+		return fmt.Errorf("interval, timeout and failure threshold cannot be negative")
+	}
+	return nil
+}
+
+// Interval is the time between two checks
+func (h HealthCheck) Interval() time.Duration {
+	if h.IntervalSeconds > 0 {
+		return time.Duration(h.IntervalSeconds) * time.Second
+	}
+	return DefaultHealthInterval * time.Second
+}
+
+// Timeout bounds a single check
+func (h HealthCheck) Timeout() time.Duration {
+	if h.TimeoutSeconds > 0 {
+		return time.Duration(h.TimeoutSeconds) * time.Second
+	}
+	return DefaultHealthTimeout * time.Second
+}
+
+// Failures is how many checks in a row must fail for the app to be unhealthy
+func (h HealthCheck) Failures() int {
+	if h.FailureThreshold > 0 {
+		return h.FailureThreshold
+	}
+	return DefaultHealthFailures
+}
+
+// SetHealthCheck sets the periodic health check of an app, nil for none
+func (cm *ConfigManager) SetHealthCheck(id string, check *HealthCheck) error {
+	if check != nil {
+		if err := check.Validate(); err != nil {
+			return err
+		}
+	}
+
+	cm.mu.Lock()
+	found := false
+	for i := range cm.Apps {
+		if cm.Apps[i].ID == id {
+			cm.Apps[i].Health = check
+			found = true
+			break
+		}
+	}
+	cm.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("app not found: %s", id)
+	}
+	return cm.Save()
+}
@@ -0,0 +1,44 @@
+package config
+
+import "testing"
+
+func TestProbeValidation(t *testing.T) {
+	tests := []struct {
+		address, url string
+		ok           bool
+	}{
+		{"localhost:8080", "http://localhost:8080/health", true},
+		{"[::1]:443", "https://example.com", true},
+		{"localhost", "localhost:8080", false},
+		{"localhost:0", "ftp://example.com", false},
+		{"localhost:70000", "http://", false},
+		{"localhost:http", "://bad", false},
+	}
+	for _, tt := range tests {
+		checks := []struct {
+			name string
+			err  error
+		}{
+			{"readiness tcp", Readiness{Type: ReadyTCP, Address: tt.address}.Validate()},
+			{"health tcp", HealthCheck{Type: HealthTCP, Address: tt.address}.Validate()},
+			{"readiness http", Readiness{Type: ReadyHTTP, URL: tt.url}.Validate()},
+			{"health http", HealthCheck{Type: HealthHTTP, URL: tt.url}.Validate()},
+		}
+		for _, c := range checks {
+			if tt.ok && c.err != nil {
+				t.Errorf("%s %q %q: %v", c.name, tt.address, tt.url, c.err)
+			}
+			if !tt.ok && c.err == nil {
+				t.Errorf("%s %q %q: accepted", c.name, tt.address, tt.url)
+			}
+		}
+	}
+
+	// Limits are still checked after a valid address
+	if err := (Readiness{Type: ReadyTCP, Address: "localhost:80", TimeoutSeconds: -1}).Validate(); err == nil {
+		t.Error("negative readiness timeout accepted")
+	}
+	if err := (HealthCheck{Type: HealthHTTP, URL: "http://localhost", IntervalSeconds: -1}).Validate(); err == nil {
+		t.Error("negative health interval accepted")
+	}
+}
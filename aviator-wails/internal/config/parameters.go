@@ -0,0 +1,242 @@
+package config
+
+import (
+	"aviator-wails/internal/cmdline"
+	"aviator-wails/internal/launcher"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parameter types
+const (
+	ParamString = "string"
+	ParamEnum   = "enum"
+	ParamNumber = "number"
+	ParamFile   = "file" // One of the files matched by the Allow globs
+)
+
+// maxParamLength bounds string values, the whole command line is limited anyway
+const maxParamLength = 1024
+
+// cmdMetachars are parsed again by cmd.exe after the argument list is built, so
+// a value holding them could chain commands when the app runs through cmd
+const cmdMetachars = "&|<>^%!\""
+
+// Parameter is a value chosen at launch time and substituted for {name} in the
+// app's arguments. A placeholder is replaced inside its own argument, so a value
+// can never add arguments of its own.
+type Parameter struct {
+	Name     string   `json:"name"`            // Letters, digits, _ and -
+	Label    string   `json:"label,omitempty"` // Shown in the launch form, defaults to Name
+	Type     string   `json:"type"`            // string, enum, number or file
+	Default  string   `json:"default,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	Options  []string `json:"options,omitempty"` // enum: the allowed values
+	Pattern  string   `json:"pattern,omitempty"` // string: regular expression the whole value must match
+	Min      *float64 `json:"min,omitempty"`     // number: bounds
+	Max      *float64 `json:"max,omitempty"`
+	Integer  bool     `json:"integer,omitempty"` // number: no decimals
+	Allow    []string `json:"allow,omitempty"`   // file: glob patterns of the files that may be picked, may use ${VAR}
+}
+
+var paramName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Validate checks the declaration of a parameter
+func (p Parameter) Validate() error {
+	if !paramName.MatchString(p.Name) {
+		return fmt.Errorf("invalid parameter name %q", p.Name)
+	}
+	switch p.Type {
+	case ParamString:
+		if p.Pattern != "" {
+			if _, err := regexp.Compile(p.Pattern); err != nil {
+				return fmt.Errorf("parameter %s: invalid pattern: %w", p.Name, err)
+			}
+		}
+	case ParamEnum:
+		if len(p.Options) == 0 {
+			return fmt.Errorf("parameter %s: enum needs options", p.Name)
+		}
+	case ParamNumber:
+		if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+			return fmt.Errorf("parameter %s: min is greater than max", p.Name)
+		}
+	case ParamFile:
+		if len(p.Allow) == 0 {
+			return fmt.Errorf("parameter %s: file needs allow patterns", p.Name)
+		}
+		for _, pattern := range p.Allow {
+			if _, err := filepath.Glob(pattern); err != nil {
+				return fmt.Errorf("parameter %s: invalid pattern %q", p.Name, pattern)
+			}
+		}
+	default:
+		return fmt.Errorf("parameter %s: invalid type %q (expected string, enum, number or file)", p.Name, p.Type)
+	}
+	return nil
+}
+
+// Files lists the files a file parameter may be set to
+func (p Parameter) Files() []string {
+	var files []string
+	for _, pattern := range p.Allow {
+		matches, _ := filepath.Glob(launcher.ExpandVars(pattern))
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() && !slices.Contains(files, m) {
+				files = append(files, m)
+			}
+		}
+	}
+	return files
+}
+
+// check validates a value given for the parameter and returns it normalized.
+// viaCmd tells that cmd.exe parses the command line, see App.runsThroughCmd.
+func (p Parameter) check(value string, viaCmd bool) (string, error) {
+	switch p.Type {
+	case ParamString:
+		if len(value) > maxParamLength {
+			return "", fmt.Errorf("%s is too long", p.Name)
+		}
+		if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "/") {
+			return "", fmt.Errorf("%s cannot start with '%c'", p.Name, value[0]) // Would read as an option, '/' on Windows
+		}
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return "", fmt.Errorf("%s contains control characters", p.Name)
+		}
+		if viaCmd && strings.ContainsAny(value, cmdMetachars) {
+			return "", fmt.Errorf("%s cannot contain any of %s", p.Name, cmdMetachars)
+		}
+		if p.Pattern != "" {
+			re, err := regexp.Compile("^(?:" + p.Pattern + ")$")
+			if err != nil || !re.MatchString(value) {
+				return "", fmt.Errorf("%s does not match the expected format", p.Name)
+			}
+		}
+		return value, nil
+
+	case ParamEnum:
+		if !slices.Contains(p.Options, value) {
+			return "", fmt.Errorf("%s must be one of: %s", p.Name, strings.Join(p.Options, ", "))
+		}
+		return value, nil
+
+	case ParamNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", fmt.Errorf("%s must be a number", p.Name)
+		}
+		if p.Integer && n != float64(int64(n)) {
+			return "", fmt.Errorf("%s must be a whole number", p.Name)
+		}
+		if p.Min != nil && n < *p.Min || p.Max != nil && n > *p.Max {
+			return "", fmt.Errorf("%s is out of range", p.Name)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+
+	case ParamFile:
+		if !slices.Contains(p.Files(), value) {
+			return "", fmt.Errorf("%s is not one of the allowed files", p.Name)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("%s has an invalid type", p.Name)
+}
+
+// ResolveArguments validates values for the app's parameters and substitutes them,
+// or their defaults, into the arguments. Values for undeclared parameters are refused.
+func (a App) ResolveArguments(values map[string]string) ([]string, error) {
+	args, err := a.Arguments()
+	if err != nil {
+		return nil, err
+	}
+	if len(a.Parameters) == 0 {
+		if len(values) > 0 {
+			return nil, fmt.Errorf("%s takes no parameters", a.Name)
+		}
+		return args, nil
+	}
+
+	for name := range values {
+		if !slices.ContainsFunc(a.Parameters, func(p Parameter) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("unknown parameter: %s", name)
+		}
+	}
+
+	viaCmd := a.runsThroughCmd()
+	pairs := make([]string, 0, 2*len(a.Parameters))
+	unset := make(map[string]bool) // Placeholders of optional parameters left empty
+	for _, p := range a.Parameters {
+		value, given := values[p.Name]
+		if !given || value == "" {
+			value = p.Default
+		}
+		if value == "" {
+			if p.Required {
+				return nil, fmt.Errorf("%s is required", p.Name)
+			}
+			unset["{"+p.Name+"}"] = true
+		} else if value, err = p.check(value, viaCmd); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, "{"+p.Name+"}", value)
+	}
+
+	// A single pass, so a value that looks like a placeholder is not expanded again
+	replacer := strings.NewReplacer(pairs...)
+	resolved := make([]string, 0, len(args))
+	for _, arg := range args {
+		if unset[arg] {
+			continue // Drop the argument rather than pass an empty one
+		}
+		resolved = append(resolved, replacer.Replace(arg))
+	}
+	return resolved, nil
+}
+
+// runsThroughCmd reports whether cmd.exe parses the app's command line: the app is
+// cmd itself, a batch file, or a script run by cmd
+func (a App) runsThroughCmd() bool {
+	if isCmdProgram(a.Path) {
+		return true
+	}
+	if a.Kind == launcher.KindScript {
+		interp, err := cmdline.Split(a.Interpreter)
+		return err == nil && len(interp) > 0 && isCmdProgram(interp[0])
+	}
+	return false
+}
+
+// isCmdProgram reports whether path names cmd.exe or a batch file, on any OS
+// since the config may have been written on Windows
+func isCmdProgram(path string) bool {
+	name := strings.ToLower(path[strings.LastIndexAny(path, `/\`)+1:])
+	switch name {
+	case "cmd", "cmd.exe":
+		return true
+	}
+	ext := filepath.Ext(name)
+	return ext == ".bat" || ext == ".cmd"
+}
+
+// validateParameters checks the parameter declarations of an app
+func validateParameters(params []Parameter) error {
+	seen := make(map[string]bool)
+	for _, p := range params {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate parameter: %s", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"aviator-wails/internal/launcher"
+	"strings"
+	"testing"
+)
+
+func TestResolveArgumentsRejectsCmdMetachars(t *testing.T) {
+	param := []Parameter{{Name: "name", Type: ParamString}}
+	injection := `x" & calc & "`
+
+	tests := []struct {
+		app    App
+		viaCmd bool
+	}{
+		{App{Path: `C:\Windows\System32\cmd.exe`, ArgList: []string{"/c", "echo", "{name}"}}, true},
+		{App{Path: "cmd", ArgList: []string{"/c", "echo {name}"}}, true},
+		{App{Path: `C:\Tools\deploy.BAT`, ArgList: []string{"{name}"}}, true},
+		{App{Path: "/opt/tools/deploy.cmd", ArgList: []string{"{name}"}}, true},
+		{App{Path: `C:\Tools\deploy.ps1`, Kind: launcher.KindScript, Interpreter: "cmd.exe /c", ArgList: []string{"{name}"}}, true},
+		{App{Path: `C:\Tools\tool.exe`, ArgList: []string{"{name}"}}, false},
+		{App{Path: `C:\Tools\deploy.py`, Kind: launcher.KindScript, Interpreter: "python -u", ArgList: []string{"{name}"}}, false},
+	}
+	for _, tt := range tests {
+		tt.app.Name = "test"
+		tt.app.Parameters = param
+
+		_, err := tt.app.ResolveArguments(map[string]string{"name": injection})
+		if tt.viaCmd && err == nil {
+			t.Errorf("%s: accepted %q", tt.app.Path, injection)
+		}
+		if !tt.viaCmd && err != nil {
+			t.Errorf("%s: refused %q: %v", tt.app.Path, injection, err)
+		}
+
+		for _, c := range strings.Split(cmdMetachars, "") {
+			_, err := tt.app.ResolveArguments(map[string]string{"name": "a" + c + "b"})
+			if tt.viaCmd && err == nil {
+				t.Errorf("%s: accepted %q", tt.app.Path, c)
+			}
+		}
+
+		args, err := tt.app.ResolveArguments(map[string]string{"name": "report 2024.txt"})
+		if err != nil {
+			t.Errorf("%s: refused a plain value: %v", tt.app.Path, err)
+		} else if args[len(args)-1] != "report 2024.txt" && args[len(args)-1] != "echo report 2024.txt" {
+			t.Errorf("%s: resolved to %q", tt.app.Path, args)
+		}
+	}
+}
+
+func TestResolveArgumentsStringChecks(t *testing.T) {
+	app := App{
+		Name:    "test",
+		Path:    "/usr/bin/tool",
+		ArgList: []string{"--tag={tag}", "{opt}"},
+		Parameters: []Parameter{
+			{Name: "tag", Type: ParamString, Pattern: `[a-z]+`, Required: true},
+			{Name: "opt", Type: ParamString},
+		},
+	}
+
+	tests := []struct {
+		values map[string]string
+		want   []string // nil when refused
+	}{
+		{map[string]string{"tag": "alpha"}, []string{"--tag=alpha"}},
+		{map[string]string{"tag": "alpha", "opt": "some value"}, []string{"--tag=alpha", "some value"}},
+		{map[string]string{"tag": "Alpha"}, nil},
+		{map[string]string{}, nil},
+		{map[string]string{"tag": "alpha", "opt": "--rm"}, nil},
+		{map[string]string{"tag": "alpha", "opt": "/delete"}, nil},
+		{map[string]string{"tag": "alpha", "opt": "a/b"}, []string{"--tag=alpha", "a/b"}},
+		{map[string]string{"tag": "alpha", "opt": "a\nb"}, nil},
+		{map[string]string{"tag": "alpha", "other": "x"}, nil},
+	}
+	for _, tt := range tests {
+		got, err := app.ResolveArguments(tt.values)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%v: accepted, resolved to %q", tt.values, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", tt.values, err)
+		} else if strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+			t.Errorf("%v: got %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestResolveArgumentsNumbers(t *testing.T) {
+	lo, hi := 1.0, 1e6
+	app := App{
+		Name:       "test",
+		Path:       "/usr/bin/tool",
+		ArgList:    []string{"--count", "{n}"},
+		Parameters: []Parameter{{Name: "n", Type: ParamNumber, Min: &lo, Max: &hi}},
+	}
+	unbounded := app
+	unbounded.Parameters = []Parameter{{Name: "n", Type: ParamNumber}}
+
+	tests := []struct {
+		value string
+		want  string // Empty when refused
+	}{
+		{"42", "42"},
+		{"1e3", "1000"},
+		{"2.50", "2.5"},
+		{"abc", ""},
+		{"NaN", ""},
+		{"Inf", ""},
+		{"+Inf", ""},
+		{"-inf", ""},
+		{"infinity", ""},
+		{"1e400", ""},
+	}
+	for _, tt := range tests {
+		for _, a := range []App{app, unbounded} {
+			args, err := a.ResolveArguments(map[string]string{"n": tt.value})
+			switch {
+			case tt.want == "" && err == nil:
+				t.Errorf("%q: accepted as %q", tt.value, args)
+			case tt.want != "" && err != nil:
+				t.Errorf("%q: %v", tt.value, err)
+			case tt.want != "" && args[1] != tt.want:
+				t.Errorf("%q: resolved to %q, want %q", tt.value, args[1], tt.want)
+			}
+		}
+	}
+}
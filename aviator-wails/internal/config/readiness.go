@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Readiness probe types
+const (
+	ReadyProcess = "process" // The process monitor sees the app running (the default)
+	ReadyTCP     = "tcp"     // Address accepts connections
+	ReadyHTTP    = "http"    // URL answers 200 OK
+	ReadyLog     = "log"     // A line of captured output matches Pattern
+)
+
+// Readiness tells when a launched app is up, for ?wait=ready launches,
+// group steps that wait and dependencies
+type Readiness struct {
+	Type           string `json:"type"`
+	Address        string `json:"address,omitempty"` // tcp: host:port
+	URL            string `json:"url,omitempty"`     // http
+	Pattern        string `json:"pattern,omitempty"` // log: regular expression, needs capture_output
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+// Validate checks a readiness probe
+func (r Readiness) Validate() error {
+	switch r.Type {
+	case ReadyProcess:
+	case ReadyTCP:
+		if err := validateAddress(r.Address); err != nil {
+			return err
+		}
+	case ReadyHTTP:
+		if err := validateHTTPURL(r.URL); err != nil {
+			return err
+		}
+	case ReadyLog:
+		if r.Pattern == "" {
+			return fmt.Errorf("log readiness needs a pattern")
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid readiness type %q (expected process, tcp, http or log)", r.Type)
+	}
+	if r.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	return nil
+}
+
+// validateAddress checks a host:port address to connect to
+func validateAddress(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q (expected host:port)", address)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port in %q", address)
+	}
+	return nil
+}
+
+// validateHTTPURL checks an http:// or https:// URL to GET
+func validateHTTPURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q (expected http:// or https://)", rawURL)
+	}
+	return nil
+}
+
+// Timeout is how long to wait for the probe to pass
+func (r Readiness) Timeout() time.Duration {
+	if r.TimeoutSeconds > 0 {
+		return time.Duration(r.TimeoutSeconds) * time.Second
+	}
+	return DefaultWaitTimeout * time.Second
+}
+
+// SetReadiness sets how to tell an app is up after a launch, nil for when its process is seen
+func (cm *ConfigManager) SetReadiness(id string, ready *Readiness) error {
+	if ready != nil {
+		if err := ready.Validate(); err != nil {
+			return err
+		}
+	}
+
+	cm.mu.Lock()
+	found := false
+	for i := range cm.Apps {
+		if cm.Apps[i].ID == id {
+			cm.Apps[i].Ready = ready
+			found = true
+			break
+		}
+	}
+	cm.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("app not found: %s", id)
+	}
+	return cm.Save()
+}
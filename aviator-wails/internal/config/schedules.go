@@ -0,0 +1,113 @@
+package config
+
+import (
+	"aviator-wails/internal/schedule"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+// Schedule actions
+const (
+	ScheduleLaunch = "launch"
+	ScheduleStop   = "stop"
+)
+
+// Schedule runs an action on an app whenever its cron expression is due
+type Schedule struct {
+	ID      string `json:"id"`
+	AppID   string `json:"app_id"`
+	Action  string `json:"action"` // launch or stop
+	Cron    string `json:"cron"`   // Five-field cron expression or @daily style macro, local time
+	Enabled bool   `json:"enabled"`
+}
+
+// Validate checks the action and the cron expression of a schedule
+func (s Schedule) Validate() error {
+	if s.Action != ScheduleLaunch && s.Action != ScheduleStop {
+		return fmt.Errorf("invalid schedule action %q (expected launch or stop)", s.Action)
+	}
+	if _, err := schedule.Parse(s.Cron); err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
+	return nil
+}
+
+func (cm *ConfigManager) LoadSchedules() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	data, err := os.ReadFile(cm.SchedulesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			cm.Schedules = []Schedule{}
+			return nil
+		}
+		return err
+	}
+
+	return json.Unmarshal(data, &cm.Schedules)
+}
+
+func (cm *ConfigManager) SaveSchedules() error {
+	cm.mu.RLock()
+	data, err := json.MarshalIndent(cm.Schedules, "", "    ")
+	cm.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(cm.SchedulesPath, data, 0644); err != nil {
+		return err
+	}
+	cm.notify(ChangeSchedules)
+	return nil
+}
+
+func (cm *ConfigManager) GetSchedules() []Schedule {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	schedules := make([]Schedule, len(cm.Schedules))
+	copy(schedules, cm.Schedules)
+	return schedules
+}
+
+// AddSchedule validates and stores a new schedule, assigning its ID
+func (cm *ConfigManager) AddSchedule(s Schedule) (Schedule, error) {
+	if err := s.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	if _, found := cm.GetAppByID(s.AppID); !found {
+		return Schedule{}, fmt.Errorf("app not found: %s", s.AppID)
+	}
+
+	s.ID = uuid.New().String()
+	cm.mu.Lock()
+	cm.Schedules = append(cm.Schedules, s)
+	cm.mu.Unlock()
+
+	return s, cm.SaveSchedules()
+}
+
+// RemoveSchedule deletes a schedule, reporting whether it existed
+func (cm *ConfigManager) RemoveSchedule(id string) (bool, error) {
+	cm.mu.Lock()
+	found := false
+	kept := []Schedule{}
+	for _, s := range cm.Schedules {
+		if s.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, s)
+	}
+	cm.Schedules = kept
+	cm.mu.Unlock()
+
+	if !found {
+		return false, nil
+	}
+	return true, cm.SaveSchedules()
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package config
+
+import "errors"
+
+// protectSecret has no store outside Windows, where as-user launches rely on the wrapper instead
+func protectSecret(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	return "", errors.New("passwords are only used on Windows")
+}
+
+// unprotectSecret reverses protectSecret
+func unprotectSecret(s string) (string, error) {
+	return protectSecret(s)
+}
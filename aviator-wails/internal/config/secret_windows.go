@@ -0,0 +1,42 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// protectSecret encrypts s with DPAPI so only the current Windows user can read it back
+func protectSecret(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	plain := []byte(s)
+	in := windows.DataBlob{Size: uint32(len(plain)), Data: &plain[0]}
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return "", err
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return base64.StdEncoding.EncodeToString(unsafe.Slice(out.Data, out.Size)), nil
+}
+
+// unprotectSecret reverses protectSecret
+func unprotectSecret(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(sealed) == 0 {
+		return "", fmt.Errorf("invalid protected secret")
+	}
+	in := windows.DataBlob{Size: uint32(len(sealed)), Data: &sealed[0]}
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return "", err
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return string(unsafe.Slice(out.Data, out.Size)), nil
+}
@@ -0,0 +1,74 @@
+package launcher
+
+import (
+	"aviator-wails/internal/cmdline"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// checkWaitDelay is how long a check's output is read after it exits or is
+// killed, in case a process it started keeps the pipes open
+const checkWaitDelay = time.Second
+
+// RunCheck runs a short command line, e.g. a health check, without a console
+// window. It fails unless the command exits with code 0 before ctx is done.
+func RunCheck(ctx context.Context, commandLine string) error {
+	words, err := cmdline.Split(commandLine)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("empty command")
+	}
+	for i := range words {
+		words[i] = ExpandVars(words[i])
+	}
+
+	cmd := exec.CommandContext(ctx, words[0], words[1:]...)
+	hideWindow(cmd)
+	var output limitedBuffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = checkWaitDelay
+
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		if ctx.Err() != nil {
+			return fmt.Errorf("timed out")
+		}
+		if out := strings.TrimSpace(output.String()); out != "" {
+			lines := strings.Split(out, "\n")
+			return fmt.Errorf("%v: %s", err, strings.TrimSpace(lines[len(lines)-1]))
+		}
+		return err
+	}
+	return nil
+}
+
+// limitedBuffer keeps the first bytes written to it
+type limitedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+const limitedBufferSize = 1024
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := limitedBufferSize - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
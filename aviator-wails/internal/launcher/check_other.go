@@ -0,0 +1,8 @@
+//go:build !windows
+
+package launcher
+
+import "os/exec"
+
+// hideWindow has nothing to hide outside Windows
+func hideWindow(cmd *exec.Cmd) {}
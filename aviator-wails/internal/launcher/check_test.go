@@ -0,0 +1,54 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunCheck(t *testing.T) {
+	tests := []struct {
+		command string
+		err     string // Expected in the error, empty for success
+	}{
+		{`sh -c 'exit 0'`, ""},
+		{`sh -c 'echo starting; echo "port closed" >&2; exit 3'`, "port closed"},
+		{`sh -c 'sleep 10'`, "timed out"},
+		{`no-such-command-for-check`, "not found"},
+		{`sh -c 'unterminated`, "unterminated"},
+		{`  `, "empty command"},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		err := RunCheck(ctx, tt.command)
+		cancel()
+		if tt.err == "" && err != nil {
+			t.Errorf("%s: %v", tt.command, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: got %v, want an error with %q", tt.command, err, tt.err)
+		}
+	}
+}
+
+func TestRunCheckOutlivingChild(t *testing.T) {
+	// A background child keeps stdout open well past the check and its timeout
+	for _, command := range []string{`sh -c 'sleep 30 & exit 0'`, `sh -c 'sleep 30 & sleep 30'`} {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		start := time.Now()
+		err := RunCheck(ctx, command)
+		cancel()
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: returned after %s", command, elapsed)
+		}
+		if strings.Contains(command, "exit 0") && err != nil {
+			t.Errorf("%s: %v", command, err)
+		}
+		if !strings.Contains(command, "exit 0") && err == nil {
+			t.Errorf("%s: expected a timeout", command)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package launcher
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+const CREATE_NO_WINDOW = 0x08000000
+
+// hideWindow keeps a console command from flashing a window on every run
+func hideWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: CREATE_NO_WINDOW}
+}
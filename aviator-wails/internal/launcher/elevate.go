@@ -0,0 +1,90 @@
+package launcher
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+// Elevation modes
+const (
+	ElevationNone     = "none"     // Aviator's own rights (the default)
+	ElevationElevated = "elevated" // Administrator / root
+	ElevationAsUser   = "as-user"  // Another account
+)
+
+// Elevation asks for a process to run with rights other than Aviator's own
+type Elevation struct {
+	Mode     string // ElevationNone if empty
+	User     string // as-user: the account, DOMAIN\user or user@domain on Windows
+	Password string // as-user on Windows
+	Wrapper  string // Elsewhere: command line put in front of the program, {user} is replaced
+}
+
+// ElevationError reports that a launch could not get the requested rights,
+// e.g. no elevation method on this system or the user declined the prompt
+type ElevationError struct {
+	Mode   string
+	Reason string
+}
+
+func (e *ElevationError) Error() string {
+	return fmt.Sprintf("cannot launch %s: %s", e.Mode, e.Reason)
+}
+
+// accountName allows DOMAIN\user and user@domain but nothing that reads as an option
+var accountName = regexp.MustCompile(`^[A-Za-z0-9_.$@\\][A-Za-z0-9_.$@\\ -]*$`)
+
+// ValidateElevation checks an elevation mode and its account
+func ValidateElevation(mode, user string) error {
+	switch mode {
+	case "", ElevationNone, ElevationElevated:
+		return nil
+	case ElevationAsUser:
+		if user == "" {
+			return fmt.Errorf("as-user needs a user name")
+		}
+		if !accountName.MatchString(user) {
+			return fmt.Errorf("invalid user name %q", user)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid elevation %q (expected none, elevated or as-user)", mode)
+}
+
+// started is a process that is running and a way to wait for it to end
+type started struct {
+	pid  int
+	wait func() exitStatus
+}
+
+// elevator starts a prepared command with the rights in opts.Elevation, one per platform
+type elevator interface {
+	start(cmd *exec.Cmd, opts Options) (started, error)
+}
+
+// startProcess starts cmd with the elevation in opts
+func startProcess(cmd *exec.Cmd, opts Options) (started, error) {
+	switch opts.Elevation.Mode {
+	case "", ElevationNone:
+		return startCmd(cmd)
+	}
+	if err := ValidateElevation(opts.Elevation.Mode, opts.Elevation.User); err != nil {
+		return started{}, err
+	}
+	return platformElevator.start(cmd, opts)
+}
+
+// startCmd starts cmd as it is
+func startCmd(cmd *exec.Cmd) (started, error) {
+	if err := cmd.Start(); err != nil {
+		return started{}, err
+	}
+	return started{
+		pid: cmd.Process.Pid,
+		wait: func() exitStatus {
+			err := cmd.Wait() // This blocks until the process finishes (and its output is copied)
+			return cmdExitStatus(cmd, err)
+		},
+	}, nil
+}
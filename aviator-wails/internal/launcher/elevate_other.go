@@ -0,0 +1,95 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"aviator-wails/internal/cmdline"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Wrappers used when the settings leave them empty. sudo -n fails rather than
+// prompt for a password nobody can type, pkexec asks through the desktop instead.
+const (
+	DefaultElevateWrapper = "sudo -n --"
+	DefaultRunAsWrapper   = "sudo -n -u {user} --"
+)
+
+// wrapperCheckDelay is how long a wrapped launch is watched for the wrapper refusing it
+const wrapperCheckDelay = time.Second
+
+// wrapperElevator runs the program through a command such as sudo or pkexec
+type wrapperElevator struct{}
+
+var platformElevator elevator = wrapperElevator{}
+
+func (wrapperElevator) start(cmd *exec.Cmd, opts Options) (started, error) {
+	e := opts.Elevation
+	wrapper := e.Wrapper
+	if wrapper == "" {
+		wrapper = DefaultElevateWrapper
+		if e.Mode == ElevationAsUser {
+			wrapper = DefaultRunAsWrapper
+		}
+	}
+	words, err := cmdline.Split(wrapper)
+	if err != nil || len(words) == 0 {
+		return started{}, &ElevationError{Mode: e.Mode, Reason: fmt.Sprintf("invalid wrapper %q", wrapper)}
+	}
+	if e.Mode == ElevationAsUser && !strings.Contains(wrapper, "{user}") {
+		return started{}, &ElevationError{Mode: e.Mode, Reason: fmt.Sprintf("wrapper %q has no {user} placeholder", wrapper)}
+	}
+	for i := range words {
+		words[i] = strings.ReplaceAll(words[i], "{user}", e.User)
+	}
+	if _, err := exec.LookPath(words[0]); err != nil {
+		return started{}, &ElevationError{Mode: e.Mode, Reason: fmt.Sprintf("%s is not installed", words[0])}
+	}
+
+	wrapped := exec.Command(words[0], append(append(words[1:], cmd.Path), cmd.Args[1:]...)...)
+	wrapped.Dir = cmd.Dir
+	wrapped.Env = cmd.Env
+	wrapped.Stdout = cmd.Stdout
+
+	// Keep the start of stderr so a refusal from the wrapper can be reported
+	var refusal limitedBuffer
+	if opts.CaptureOutput {
+		wrapped.Stderr = io.MultiWriter(cmd.Stderr, &refusal)
+	} else {
+		wrapped.Stderr = &refusal
+	}
+
+	proc, err := startCmd(wrapped)
+	if err != nil {
+		return started{}, err
+	}
+
+	// The wrapper fails straight away when it cannot elevate, e.g. sudo -n without a rule.
+	// Any other early exit is the program's own and is reported like a normal exit.
+	done := make(chan exitStatus, 1)
+	go func() { done <- proc.wait() }()
+	select {
+	case status := <-done:
+		if reason := strings.TrimSpace(refusal.String()); wrapperRefused(words[0], status.code, reason) {
+			return started{}, &ElevationError{Mode: e.Mode, Reason: fmt.Sprintf("%s refused: %s", words[0], reason)}
+		}
+		return started{pid: proc.pid, wait: func() exitStatus { return status }}, nil
+	case <-time.After(wrapperCheckDelay):
+	}
+	return started{pid: proc.pid, wait: func() exitStatus { return <-done }}, nil
+}
+
+// wrapperRefused tells a wrapper turning a launch down from the program failing.
+// pkexec has exit codes of its own; sudo, doas and the like exit with 1 and
+// prefix their messages with their name, which the program's output lacks.
+func wrapperRefused(wrapper string, code int, stderr string) bool {
+	name := filepath.Base(wrapper)
+	if name == "pkexec" {
+		return code == 126 || code == 127 // Dialog dismissed, not authorized
+	}
+	return code == 1 && strings.HasPrefix(stderr, name+":")
+}
@@ -0,0 +1,99 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeWrapper writes a script named like a wrapper that runs its arguments,
+// or refuses like sudo -n when refuse is set
+func fakeWrapper(t *testing.T, name string, refuse bool) string {
+	t.Helper()
+	script := "#!/bin/sh\nexec \"$@\"\n"
+	if refuse {
+		script = "#!/bin/sh\necho \"" + name + ": a password is required\" >&2\nexit 1\n"
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func startWrapped(wrapper string, program ...string) (started, error) {
+	cmd := exec.Command(program[0], program[1:]...)
+	return wrapperElevator{}.start(cmd, Options{Elevation: Elevation{Mode: ElevationElevated, Wrapper: wrapper}})
+}
+
+func TestWrapperRefusal(t *testing.T) {
+	_, err := startWrapped(fakeWrapper(t, "sudo", true), "/bin/sh", "-c", "exit 0")
+	var elevationErr *ElevationError
+	if !errors.As(err, &elevationErr) {
+		t.Fatalf("got %v, want an ElevationError", err)
+	}
+
+	_, err = startWrapped("no-such-wrapper --", "/bin/sh", "-c", "exit 0")
+	if !errors.As(err, &elevationErr) {
+		t.Errorf("missing wrapper: got %v, want an ElevationError", err)
+	}
+}
+
+func TestWrappedProgramEarlyExit(t *testing.T) {
+	// The program itself fails at once, with output on stderr and exit code 1
+	proc, err := startWrapped(fakeWrapper(t, "sudo", false), "/bin/sh", "-c", "echo 'config: missing' >&2; exit 1")
+	if err != nil {
+		t.Fatalf("the program's own exit was reported as %v", err)
+	}
+	if proc.pid == 0 {
+		t.Error("no PID for the launch")
+	}
+	if status := proc.wait(); status.code != 1 {
+		t.Errorf("got exit %+v, want code 1", status)
+	}
+}
+
+func TestWrappedProgramRuns(t *testing.T) {
+	proc, err := startWrapped(fakeWrapper(t, "sudo", false), "/bin/sh", "-c", "sleep 2; exit 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan exitStatus, 1)
+	go func() { done <- proc.wait() }()
+	select {
+	case status := <-done:
+		if status.code != 3 {
+			t.Errorf("got exit %+v, want code 3", status)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("wait did not return")
+	}
+}
+
+func TestWrapperRefused(t *testing.T) {
+	tests := []struct {
+		wrapper string
+		code    int
+		stderr  string
+		want    bool
+	}{
+		{"sudo", 1, "sudo: a password is required", true},
+		{"/usr/bin/doas", 1, "doas: Authentication failed", true},
+		{"sudo", 1, "Error: port 80 in use", false},
+		{"sudo", 1, "", false},
+		{"sudo", 2, "sudo: something", false},
+		{"pkexec", 126, "", true},
+		{"/usr/bin/pkexec", 127, "Error executing command as another user: Not authorized", true},
+		{"pkexec", 1, "pkexec: oops", false},
+	}
+	for _, tt := range tests {
+		if got := wrapperRefused(tt.wrapper, tt.code, tt.stderr); got != tt.want {
+			t.Errorf("wrapperRefused(%q, %d, %q) = %v, want %v", tt.wrapper, tt.code, tt.stderr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	shell32             = syscall.NewLazyDLL("shell32.dll")
+	procShellExecuteExW = shell32.NewProc("ShellExecuteExW")
+
+	advapi32                    = syscall.NewLazyDLL("advapi32.dll")
+	procCreateProcessWithLogonW = advapi32.NewProc("CreateProcessWithLogonW")
+
+	procGetProcessId = kernel32.NewProc("GetProcessId")
+)
+
+const (
+	SEE_MASK_NOCLOSEPROCESS = 0x00000040
+	SEE_MASK_NOASYNC        = 0x00000100
+	SW_SHOWNORMAL           = 1
+	LOGON_WITH_PROFILE      = 0x00000001
+	ERROR_CANCELLED         = 1223
+	ERROR_LOGON_FAILURE     = 1326
+)
+
+// shellExecuteInfo mirrors SHELLEXECUTEINFOW
+type shellExecuteInfo struct {
+	cbSize       uint32
+	fMask        uint32
+	hwnd         uintptr
+	lpVerb       *uint16
+	lpFile       *uint16
+	lpParameters *uint16
+	lpDirectory  *uint16
+	nShow        int32
+	hInstApp     uintptr
+	lpIDList     uintptr
+	lpClass      *uint16
+	hkeyClass    uintptr
+	dwHotKey     uint32
+	hIcon        uintptr
+	hProcess     syscall.Handle
+}
+
+// shellElevator uses the UAC prompt for elevated launches and a logon for as-user ones
+type shellElevator struct{}
+
+var platformElevator elevator = shellElevator{}
+
+func (shellElevator) start(cmd *exec.Cmd, opts Options) (started, error) {
+	e := opts.Elevation
+
+	// Neither API can hand the process our pipes, and both start it with a fresh environment
+	if opts.CaptureOutput {
+		return started{}, &ElevationError{Mode: e.Mode, Reason: "output capture is not available"}
+	}
+	if len(opts.Env) > 0 {
+		return started{}, &ElevationError{Mode: e.Mode, Reason: "environment variables cannot be set"}
+	}
+
+	if e.Mode == ElevationAsUser {
+		return startWithLogon(cmd, e)
+	}
+	return startRunAs(cmd, e)
+}
+
+// startRunAs starts cmd through the UAC prompt
+func startRunAs(cmd *exec.Cmd, e Elevation) (started, error) {
+	verb, _ := syscall.UTF16PtrFromString("runas")
+	file, err := syscall.UTF16PtrFromString(cmd.Path)
+	if err != nil {
+		return started{}, err
+	}
+	params, err := syscall.UTF16PtrFromString(joinArgs(cmd.Args[1:]))
+	if err != nil {
+		return started{}, err
+	}
+	var dir *uint16
+	if cmd.Dir != "" {
+		if dir, err = syscall.UTF16PtrFromString(cmd.Dir); err != nil {
+			return started{}, err
+		}
+	}
+
+	info := shellExecuteInfo{
+		fMask:        SEE_MASK_NOCLOSEPROCESS | SEE_MASK_NOASYNC,
+		lpVerb:       verb,
+		lpFile:       file,
+		lpParameters: params,
+		lpDirectory:  dir,
+		nShow:        SW_SHOWNORMAL,
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+
+	ret, _, callErr := procShellExecuteExW.Call(uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		if errors.Is(callErr, syscall.Errno(ERROR_CANCELLED)) {
+			return started{}, &ElevationError{Mode: e.Mode, Reason: "the administrator prompt was declined"}
+		}
+		return started{}, &ElevationError{Mode: e.Mode, Reason: callErr.Error()}
+	}
+	if info.hProcess == 0 {
+		return started{}, &ElevationError{Mode: e.Mode, Reason: "no process was started"}
+	}
+
+	pid, _, _ := procGetProcessId.Call(uintptr(info.hProcess))
+	return started{pid: int(pid), wait: func() exitStatus { return waitHandle(info.hProcess) }}, nil
+}
+
+// startWithLogon starts cmd as another account with its own profile and environment
+func startWithLogon(cmd *exec.Cmd, e Elevation) (started, error) {
+	user, domain := e.User, "."
+	if d, u, ok := strings.Cut(e.User, `\`); ok {
+		user, domain = u, d
+	} else if strings.Contains(e.User, "@") {
+		domain = "" // A UPN carries its domain
+	}
+
+	userPtr, err := syscall.UTF16PtrFromString(user)
+	if err != nil {
+		return started{}, err
+	}
+	var domainPtr *uint16
+	if domain != "" {
+		domainPtr, _ = syscall.UTF16PtrFromString(domain)
+	}
+	password, err := syscall.UTF16PtrFromString(e.Password)
+	if err != nil {
+		return started{}, err
+	}
+	cmdLine, err := syscall.UTF16PtrFromString(joinArgs(append([]string{cmd.Path}, cmd.Args[1:]...)))
+	if err != nil {
+		return started{}, err
+	}
+	var dir *uint16
+	if cmd.Dir != "" {
+		if dir, err = syscall.UTF16PtrFromString(cmd.Dir); err != nil {
+			return started{}, err
+		}
+	}
+
+	si := syscall.StartupInfo{}
+	si.Cb = uint32(unsafe.Sizeof(si))
+	var pi syscall.ProcessInformation
+
+	ret, _, callErr := procCreateProcessWithLogonW.Call(
+		uintptr(unsafe.Pointer(userPtr)),
+		uintptr(unsafe.Pointer(domainPtr)),
+		uintptr(unsafe.Pointer(password)),
+		LOGON_WITH_PROFILE,
+		0,
+		uintptr(unsafe.Pointer(cmdLine)),
+		0,
+		0, // The account's own environment
+		uintptr(unsafe.Pointer(dir)),
+		uintptr(unsafe.Pointer(&si)),
+		uintptr(unsafe.Pointer(&pi)),
+	)
+	if ret == 0 {
+		if errors.Is(callErr, syscall.Errno(ERROR_LOGON_FAILURE)) {
+			return started{}, &ElevationError{Mode: e.Mode, Reason: fmt.Sprintf("logon failed for %s: unknown user name or bad password", e.User)}
+		}
+		return started{}, &ElevationError{Mode: e.Mode, Reason: callErr.Error()}
+	}
+	syscall.CloseHandle(pi.Thread)
+
+	return started{pid: int(pi.ProcessId), wait: func() exitStatus { return waitHandle(pi.Process) }}, nil
+}
+
+// waitHandle waits for the process behind h to end, then closes h
+func waitHandle(h syscall.Handle) exitStatus {
+	defer syscall.CloseHandle(h)
+
+	if _, err := syscall.WaitForSingleObject(h, syscall.INFINITE); err != nil {
+		return exitStatus{code: -1, reason: err.Error()}
+	}
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return exitStatus{code: -1, reason: err.Error()}
+	}
+	status := exitStatus{code: int(code)}
+	if code != 0 {
+		status.reason = fmt.Sprintf("exit status %d", code)
+	}
+	return status
+}
+
+// joinArgs quotes arguments the way the C runtime splits them
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = syscall.EscapeArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,56 @@
+package launcher
+
+import (
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// varRef matches ${VAR} references. The bare $VAR form is deliberately not
+// expanded so values such as passwords may contain a literal '$'.
+var varRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandVars replaces ${VAR} references with values from the host environment.
+// Unset variables expand to the empty string.
+func ExpandVars(s string) string {
+	return varRef.ReplaceAllStringFunc(s, func(ref string) string {
+		return os.Getenv(varRef.FindStringSubmatch(ref)[1])
+	})
+}
+
+// buildEnv returns Aviator's environment with the app's variables applied on top.
+// Values are expanded against the host environment, so "PATH": "C:\tools;${PATH}"
+// prepends to the inherited PATH.
+func buildEnv(overrides map[string]string) []string {
+	env := os.Environ()
+	if len(overrides) == 0 {
+		return env
+	}
+
+	// Sorted for a deterministic result when keys differ only by case
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		env = setEnv(env, key, ExpandVars(overrides[key]))
+	}
+	return env
+}
+
+// setEnv replaces or appends KEY=value in an environment list.
+// Variable names are case-insensitive on Windows ("Path" and "PATH" are the same).
+func setEnv(env []string, key, value string) []string {
+	for i, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if name == key || (runtime.GOOS == "windows" && strings.EqualFold(name, key)) {
+			env[i] = key + "=" + value
+			return env
+		}
+	}
+	return append(env, key+"="+value)
+}
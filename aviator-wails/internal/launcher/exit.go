@@ -0,0 +1,92 @@
+package launcher
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ExitInfo describes how a launched process ended
+type ExitInfo struct {
+	AppID     string    `json:"app_id"`
+	PID       int       `json:"pid"`
+	ExitCode  int       `json:"exit_code"` // -1 if terminated by a signal or unknown
+	StartedAt time.Time `json:"started_at"`
+	ExitedAt  time.Time `json:"exited_at"`
+	Duration  float64   `json:"duration_seconds"`
+	Stopped   bool      `json:"stopped"`          // Aviator was asked to stop it
+	Abnormal  bool      `json:"abnormal"`         // Non-zero exit (or signal) that nobody asked for
+	Reason    string    `json:"reason,omitempty"` // e.g. "exit status 3", "signal: killed"
+}
+
+var (
+	exitListeners []func(ExitInfo)
+	exitMutex     sync.RWMutex
+)
+
+// OnExit registers fn to be called, from the monitoring goroutine,
+// every time a launched process ends
+func OnExit(fn func(ExitInfo)) {
+	exitMutex.Lock()
+	defer exitMutex.Unlock()
+	exitListeners = append(exitListeners, fn)
+}
+
+func notifyExit(info ExitInfo) {
+	exitMutex.RLock()
+	listeners := append([]func(ExitInfo){}, exitListeners...)
+	exitMutex.RUnlock()
+
+	for _, fn := range listeners {
+		fn(info)
+	}
+}
+
+// exitStatus is what waiting on a process reported
+type exitStatus struct {
+	code   int    // -1 if terminated by a signal or unknown
+	reason string // Empty for a clean exit
+}
+
+// cmdExitStatus converts the result of cmd.Wait
+func cmdExitStatus(cmd *exec.Cmd, waitErr error) exitStatus {
+	status := exitStatus{code: -1}
+	if state := cmd.ProcessState; state != nil {
+		status.code = state.ExitCode()
+		if !state.Success() {
+			status.reason = state.String()
+		}
+	} else if waitErr != nil {
+		status.reason = waitErr.Error()
+	}
+	return status
+}
+
+// newExitInfo builds the exit record of a process
+func newExitInfo(appID string, pid int, startedAt time.Time, status exitStatus, stopped bool) ExitInfo {
+	info := ExitInfo{
+		AppID:     appID,
+		PID:       pid,
+		ExitCode:  status.code,
+		Reason:    status.reason,
+		StartedAt: startedAt,
+		ExitedAt:  time.Now(),
+		Stopped:   stopped,
+	}
+	info.Duration = info.ExitedAt.Sub(startedAt).Seconds()
+	info.Abnormal = !stopped && info.ExitCode != 0
+	return info
+}
+
+// String summarizes the exit for log files
+func (e ExitInfo) String() string {
+	switch {
+	case e.Stopped:
+		return fmt.Sprintf("PID %d stopped after %.1fs (exit code %d)", e.PID, e.Duration, e.ExitCode)
+	case e.Abnormal:
+		return fmt.Sprintf("PID %d exited abnormally after %.1fs: %s", e.PID, e.Duration, e.Reason)
+	default:
+		return fmt.Sprintf("PID %d exited after %.1fs", e.PID, e.Duration)
+	}
+}
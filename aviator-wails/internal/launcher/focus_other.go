@@ -0,0 +1,9 @@
+//go:build !windows
+
+package launcher
+
+// BringToFront is not supported without a window manager API, it reports
+// that no window was found
+func BringToFront(pids []int) bool {
+	return false
+}
@@ -0,0 +1,45 @@
+package launcher
+
+var (
+	procIsWindowVisible     = user32.NewProc("IsWindowVisible")
+	procIsIconic            = user32.NewProc("IsIconic")
+	procGetWindow           = user32.NewProc("GetWindow")
+	procShowWindow          = user32.NewProc("ShowWindow")
+	procSetForegroundWindow = user32.NewProc("SetForegroundWindow")
+	procKeybdEvent          = user32.NewProc("keybd_event")
+)
+
+const (
+	GW_OWNER        = 4
+	SW_RESTORE      = 9
+	VK_MENU         = 0x12
+	KEYEVENTF_KEYUP = 0x0002
+)
+
+// BringToFront restores and activates the main window of the first process
+// that has one, reporting whether a window was found
+func BringToFront(pids []int) bool {
+	for _, pid := range pids {
+		for _, hwnd := range processWindows(pid) {
+			// Skip hidden helper windows and dialogs owned by another window
+			if visible, _, _ := procIsWindowVisible.Call(hwnd); visible == 0 {
+				continue
+			}
+			if owner, _, _ := procGetWindow.Call(hwnd, GW_OWNER); owner != 0 {
+				continue
+			}
+
+			if iconic, _, _ := procIsIconic.Call(hwnd); iconic != 0 {
+				procShowWindow.Call(hwnd, SW_RESTORE)
+			}
+
+			// Windows only lets the foreground process change the foreground window,
+			// a synthetic Alt press lifts that lock for the next call
+			procKeybdEvent.Call(VK_MENU, 0, 0, 0)
+			procKeybdEvent.Call(VK_MENU, 0, KEYEVENTF_KEYUP, 0)
+			procSetForegroundWindow.Call(hwnd)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package launcher
+
+import (
+	"aviator-wails/internal/cmdline"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Kinds of launch target
+const (
+	KindExecutable = "executable" // A program, run directly (the default)
+	KindDocument   = "document"   // A file opened with its default application
+	KindURL        = "url"        // A URL opened with its default handler, e.g. https: or steam:
+	KindScript     = "script"     // A script run by an interpreter
+)
+
+// Opener builds the command that hands a document or URL to its default handler
+type Opener interface {
+	Command(target string) (name string, args []string)
+}
+
+// CommandOpener runs a program with the target as its last argument, e.g. xdg-open
+type CommandOpener struct {
+	Name string
+	Args []string // Placed before the target
+}
+
+func (o CommandOpener) Command(target string) (string, []string) {
+	return o.Name, append(slices.Clone(o.Args), target)
+}
+
+var (
+	opener   Opener = defaultOpener()
+	openerMu sync.RWMutex
+)
+
+// SetOpener replaces the platform opener, e.g. with a stand-in for tests or another desktop
+func SetOpener(o Opener) {
+	openerMu.Lock()
+	defer openerMu.Unlock()
+	opener = o
+}
+
+func openCommand(target string) (string, []string) {
+	openerMu.RLock()
+	defer openerMu.RUnlock()
+	return opener.Command(target)
+}
+
+// ValidateTarget checks that a launch target is well formed for its kind.
+// Files are not required to exist yet.
+func ValidateTarget(kind, path, interpreter string, args []string) error {
+	switch kind {
+	case "", KindExecutable:
+	case KindDocument:
+		if len(args) > 0 {
+			return fmt.Errorf("documents take no arguments")
+		}
+	case KindURL:
+		if len(args) > 0 {
+			return fmt.Errorf("URLs take no arguments")
+		}
+		return validateURL(path)
+	case KindScript:
+		interp, err := cmdline.Split(interpreter)
+		if err != nil {
+			return fmt.Errorf("invalid interpreter: %w", err)
+		}
+		if len(interp) == 0 {
+			return fmt.Errorf("scripts need an interpreter, e.g. python or powershell -File")
+		}
+	default:
+		return fmt.Errorf("invalid kind %q (expected executable, document, url or script)", kind)
+	}
+	return nil
+}
+
+// validateURL accepts absolute URLs with a scheme longer than a drive letter
+func validateURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || len(u.Scheme) < 2 {
+		return fmt.Errorf("invalid URL: %s", target)
+	}
+	return nil
+}
+
+// resolveCommand returns the program and arguments that start a target of the given kind,
+// and the folder a relative working directory starts from
+func resolveCommand(path string, args []string, opts Options) (string, []string, string, error) {
+	if err := ValidateTarget(opts.Kind, path, opts.Interpreter, args); err != nil {
+		return "", nil, "", err
+	}
+
+	switch opts.Kind {
+	case KindURL:
+		name, openArgs := openCommand(path)
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = os.TempDir()
+		}
+		return name, openArgs, home, nil
+
+	case KindDocument:
+		if _, err := os.Stat(path); err != nil {
+			return "", nil, "", fmt.Errorf("document not found: %s", path)
+		}
+		name, openArgs := openCommand(path)
+		return name, openArgs, filepath.Dir(path), nil
+
+	case KindScript:
+		if _, err := os.Stat(path); err != nil {
+			return "", nil, "", fmt.Errorf("script not found: %s", path)
+		}
+		interp, _ := cmdline.Split(opts.Interpreter)
+		cmdArgs := append(interp[1:], path)
+		return interp[0], append(cmdArgs, args...), filepath.Dir(path), nil
+
+	default:
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return "", nil, "", fmt.Errorf("executable not found: %s", path)
+		}
+		return path, args, filepath.Dir(path), nil
+	}
+}
@@ -0,0 +1,122 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// standInOpener records the targets it is given in a file instead of opening them
+func standInOpener(t *testing.T) (record string) {
+	t.Helper()
+	record = filepath.Join(t.TempDir(), "opened.txt")
+	// sh -c script name target: the target is $1
+	SetOpener(CommandOpener{Name: "/bin/sh", Args: []string{"-c", `printf '%s\n' "$1" >> "` + record + `"`, "opener"}})
+	t.Cleanup(func() { SetOpener(defaultOpener()) })
+	return record
+}
+
+func TestResolveCommandWithOpener(t *testing.T) {
+	SetOpener(CommandOpener{Name: "open-with", Args: []string{"--new-window"}})
+	t.Cleanup(func() { SetOpener(defaultOpener()) })
+
+	doc := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(doc, []byte("%PDF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		kind, path string
+		wantArgs   []string
+		wantDir    string
+	}{
+		{KindURL, "steam://rungameid/440", []string{"--new-window", "steam://rungameid/440"}, ""},
+		{KindDocument, doc, []string{"--new-window", doc}, filepath.Dir(doc)},
+	}
+	for _, tt := range tests {
+		name, args, dir, err := resolveCommand(tt.path, nil, Options{Kind: tt.kind})
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		if name != "open-with" || !slices.Equal(args, tt.wantArgs) {
+			t.Errorf("%s: got %s %q", tt.path, name, args)
+		}
+		if tt.wantDir != "" && dir != tt.wantDir {
+			t.Errorf("%s: started in %s, want %s", tt.path, dir, tt.wantDir)
+		}
+	}
+
+	for _, bad := range []struct {
+		kind, path string
+		args       []string
+	}{
+		{KindDocument, filepath.Join(t.TempDir(), "missing.pdf"), nil},
+		{KindDocument, doc, []string{"--page", "2"}},
+		{KindURL, "C:/not/a/url", nil},
+		{KindURL, "https://example.com", []string{"extra"}},
+	} {
+		if _, _, _, err := resolveCommand(bad.path, bad.args, Options{Kind: bad.kind}); err == nil {
+			t.Errorf("%s %q: expected an error", bad.path, bad.args)
+		}
+	}
+}
+
+func TestLaunchWithStandInOpener(t *testing.T) {
+	record := standInOpener(t)
+	doc := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(doc, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	targets := []struct{ id, kind, path string }{
+		{"test-url", KindURL, "https://example.com/dashboard?tab=1"},
+		{"test-doc", KindDocument, doc},
+	}
+	for _, tt := range targets {
+		pid, err := RunExecutableWithTracking(tt.id, tt.id, tt.path, nil, Options{Kind: tt.kind})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if pid == 0 {
+			t.Errorf("%s: no PID", tt.path)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(record)
+		opened := strings.Fields(string(data))
+		slices.Sort(opened)
+		want := []string{doc, targets[0].path}
+		slices.Sort(want)
+		if slices.Equal(opened, want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("opened %q, want %q", opened, want)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// The opener exits once it has handed the target over
+	for _, tt := range targets {
+		for {
+			if exit, ok := GetLastExit(tt.id); ok {
+				if exit.ExitCode != 0 {
+					t.Errorf("%s: opener exited with %d", tt.path, exit.ExitCode)
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: opener did not exit", tt.path)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package launcher
 
 import (
+	"aviator-wails/internal/cmdline"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
 	"sync"
+	"time"
 )
 
 // ProcessInfo tracks running processes
@@ -15,7 +17,11 @@ type ProcessInfo struct {
 	AppName   string
 	Pid       int
 	IsRunning bool
-	cmd       *exec.Cmd
+	StartedAt time.Time
+	LastExit  *ExitInfo // How the previous launch ended, nil if none has ended yet
+
+	stream        *outputStream // Live output, nil unless output is captured
+	stopRequested bool          // Set by StopProcesses so the exit is not reported as a crash
 }
 
 var (
@@ -23,61 +29,161 @@ var (
 	processMutex     sync.RWMutex
 )
 
-// RunExecutableWithTracking launches the application and tracks its process
-func RunExecutableWithTracking(appID, appName, path, args string) (int, error) {
-	fmt.Printf("[Launcher] Launching: %s Args: %s\n", path, args)
+// Options holds the per-app process settings applied at launch
+type Options struct {
+	Kind        string // KindExecutable if empty
+	Interpreter string // KindScript: the interpreter command line, e.g. "python -u"
 
-	// Validate path exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return 0, fmt.Errorf("executable not found: %s", path)
+	WorkingDir string            // Empty for the executable's folder, relative paths start from there
+	Env        map[string]string // Added to Aviator's environment, values may reference ${VAR}
+
+	CaptureOutput bool // Write stdout/stderr to the app's rotating log file
+
+	Elevation Elevation // Rights to run with, Aviator's own if the mode is empty
+}
+
+// RunExecutableWithTracking launches the application and tracks its process.
+// path is an executable, document, URL or script depending on opts.Kind.
+// args are passed to the process verbatim, no further splitting is done.
+func RunExecutableWithTracking(appID, appName, path string, args []string, opts Options) (int, error) {
+	fmt.Printf("[Launcher] Launching: %s Args: %q\n", path, args)
+
+	name, cmdArgs, baseDir, err := resolveCommand(path, args, opts)
+	if err != nil {
+		return 0, err
 	}
 
-	// Parse arguments
-	var cmdArgs []string
-	if args != "" {
-		cmdArgs = strings.Fields(args)
+	dir, err := resolveWorkingDir(baseDir, opts.WorkingDir)
+	if err != nil {
+		return 0, err
 	}
 
-	cmd := exec.Command(path, cmdArgs...)
-	cmd.Dir = filepath.Dir(path)
+	cmd := exec.Command(name, cmdArgs...)
+	cmd.Dir = dir
+	cmd.Env = buildEnv(opts.Env)
 
-	if err := cmd.Start(); err != nil {
-		return 0, err
+	var output *rotatingLog
+	var stream *outputStream
+	if opts.CaptureOutput {
+		output, err = acquireLog(appID)
+		if err != nil {
+			return 0, fmt.Errorf("cannot open log file: %w", err)
+		}
+		stream = newOutputStream()
+
+		// Same writer for both streams keeps their lines in order
+		tee := io.MultiWriter(output, stream)
+		cmd.Stdout = tee
+		cmd.Stderr = tee
+		output.writeMarker("launching %s %s", name, cmdline.Join(cmdArgs))
 	}
 
-	pid := 0
-	if cmd.Process != nil {
-		pid = cmd.Process.Pid
+	startedAt := time.Now()
+	proc, err := startProcess(cmd, opts)
+	if err != nil {
+		if output != nil {
+			output.writeMarker("launch failed: %v", err)
+			releaseLog(appID, output)
+		}
+		return 0, err
+	}
 
-		// Store process info
+	pid := proc.pid
+	if pid != 0 {
+		// Store process info, keeping the outcome of the previous launch
 		processMutex.Lock()
+		var lastExit *ExitInfo
+		if prev, exists := runningProcesses[appID]; exists {
+			lastExit = prev.LastExit
+		}
 		runningProcesses[appID] = &ProcessInfo{
 			AppID:     appID,
 			AppName:   appName,
 			Pid:       pid,
 			IsRunning: true,
-			cmd:       cmd,
+			StartedAt: startedAt,
+			LastExit:  lastExit,
+			stream:    stream,
 		}
 		processMutex.Unlock()
 
 		// Monitor process in background
-		go monitorProcess(appID, cmd)
+		go monitorProcess(appID, proc, startedAt, output, stream)
 	}
 
 	return pid, nil
 }
 
-// monitorProcess waits for the process to finish and updates status
-func monitorProcess(appID string, cmd *exec.Cmd) {
-	cmd.Wait() // This blocks until the process finishes
+// resolveWorkingDir returns the directory to start the process in,
+// baseDir unless the app sets one (relative paths start from baseDir)
+func resolveWorkingDir(baseDir, workingDir string) (string, error) {
+	if workingDir == "" {
+		return baseDir, nil
+	}
+
+	dir := ExpandVars(workingDir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(baseDir, dir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("working directory not found: %s", dir)
+	}
+	return dir, nil
+}
+
+// monitorProcess waits for the process to finish, records how it ended and updates status
+func monitorProcess(appID string, proc started, startedAt time.Time, output *rotatingLog, stream *outputStream) {
+	status := proc.wait()
+
+	if stream != nil {
+		stream.close()
+	}
 
 	processMutex.Lock()
-	if info, exists := runningProcesses[appID]; exists {
+	info, exists := runningProcesses[appID]
+	current := exists && info.Pid == proc.pid && info.StartedAt.Equal(startedAt) // The app may have been launched again meanwhile
+	exit := newExitInfo(appID, proc.pid, startedAt, status, current && info.stopRequested)
+	if current {
 		info.IsRunning = false
+		info.LastExit = &exit
 	}
 	processMutex.Unlock()
 
-	fmt.Printf("[Launcher] Process for app %s has terminated\n", appID)
+	if output != nil {
+		output.writeMarker("%s", exit)
+		releaseLog(appID, output)
+	}
+
+	fmt.Printf("[Launcher] Process for app %s has terminated: %s\n", appID, exit)
+	notifyExit(exit)
+}
+
+// GetLastExit returns how the last finished launch of an app ended
+func GetLastExit(appID string) (ExitInfo, bool) {
+	processMutex.RLock()
+	defer processMutex.RUnlock()
+
+	if info, exists := runningProcesses[appID]; exists && info.LastExit != nil {
+		return *info.LastExit, true
+	}
+	return ExitInfo{}, false
+}
+
+// markStopRequested flags the tracked launches among pids as stopped on purpose
+func markStopRequested(pids []int) {
+	processMutex.Lock()
+	defer processMutex.Unlock()
+
+	for _, info := range runningProcesses {
+		if !info.IsRunning {
+			continue
+		}
+		for _, pid := range pids {
+			if info.Pid == pid {
+				info.stopRequested = true
+			}
+		}
+	}
 }
 
 // GetProcessStatus returns the status of a specific app
@@ -111,9 +217,9 @@ func RunExecutable(path string, args string) error {
 		return fmt.Errorf("executable not found: %s", path)
 	}
 
-	var cmdArgs []string
-	if args != "" {
-		cmdArgs = strings.Fields(args)
+	cmdArgs, err := cmdline.Split(args)
+	if err != nil {
+		return err
 	}
 
 	cmd := exec.Command(path, cmdArgs...)
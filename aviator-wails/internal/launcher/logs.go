@@ -0,0 +1,217 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxLogSize is the size at which an app log is rotated
+	MaxLogSize = 1 << 20 // 1 MiB
+	// MaxLogBackups is how many rotated files (app.log.1 ... app.log.N) are kept
+	MaxLogBackups = 3
+)
+
+var (
+	logDir     string
+	logWriters = make(map[string]*rotatingLog) // appID -> shared writer
+	logMutex   sync.Mutex
+)
+
+// SetLogDir sets the folder captured output is written to
+func SetLogDir(dir string) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	logDir = dir
+}
+
+// logPath returns the current log file of an app
+func logPath(appID string) string {
+	return filepath.Join(logDir, appID+".log")
+}
+
+// rotatingLog is an append-only file that is rotated when it grows past MaxLogSize.
+// It is shared by all running launches of the same app and reference counted.
+type rotatingLog struct {
+	path   string
+	file   *os.File // Nil after a failed rotation, reopened by the next write
+	size   int64
+	refs   int
+	failed error // Error of the last write, reported once until a write succeeds
+	mu     sync.Mutex
+}
+
+// acquireLog opens (or reuses) the log writer of an app
+func acquireLog(appID string) (*rotatingLog, error) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	if logDir == "" {
+		return nil, fmt.Errorf("log directory not configured")
+	}
+	if l, ok := logWriters[appID]; ok {
+		l.refs++
+		return l, nil
+	}
+
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, err
+	}
+	l := &rotatingLog{path: logPath(appID), refs: 1}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	logWriters[appID] = l
+	return l, nil
+}
+
+// releaseLog drops a reference and closes the file once no launch uses it
+func releaseLog(appID string, l *rotatingLog) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	l.refs--
+	if l.refs > 0 {
+		return
+	}
+	delete(logWriters, appID)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		l.file.Close()
+	}
+}
+
+func (l *rotatingLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if the file would grow past MaxLogSize.
+// It never fails: the log shares an io.MultiWriter with the live output stream,
+// and an error (e.g. a full disk) would stop copying the child's output. Errors
+// are logged instead and the output is lost for the log file only.
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.write(p)
+	switch {
+	case err != nil && l.failed == nil:
+		log.Printf("Error writing %s, output is not logged until it recovers: %v", l.path, err)
+	case err == nil && l.failed != nil:
+		log.Printf("Writing %s again", l.path)
+	}
+	l.failed = err
+	return len(p), nil
+}
+
+func (l *rotatingLog) write(p []byte) error {
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return err
+		}
+	}
+	if l.size > 0 && l.size+int64(len(p)) > MaxLogSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return err
+}
+
+// rotate shifts app.log -> app.log.1 -> ... -> app.log.N, dropping the oldest
+func (l *rotatingLog) rotate() error {
+	l.file.Close()
+	l.file = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", l.path, MaxLogBackups))
+	for i := MaxLogBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	os.Rename(l.path, l.path+".1")
+
+	return l.open()
+}
+
+// writeMarker writes a header line separating launches in the log
+func (l *rotatingLog) writeMarker(format string, args ...interface{}) {
+	fmt.Fprintf(l, "==== %s %s ====\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
+
+// TailLog returns the last n lines captured for an app, reading into the
+// previous rotated file if the current one is shorter
+func TailLog(appID string, n int) ([]string, error) {
+	logMutex.Lock()
+	path := logPath(appID)
+	logMutex.Unlock()
+
+	var lines []string
+	for _, file := range []string{path, path + ".1"} {
+		fileLines, err := readLines(file)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		lines = append(fileLines, lines...)
+		if len(lines) >= n {
+			break
+		}
+	}
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+	return lines, nil
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Split(scanLogLines)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// scanLogLines is bufio.ScanLines, except that lines longer than maxLineLength
+// are split as in the live stream instead of failing with bufio.ErrTooLong
+func scanLogLines(data []byte, atEOF bool) (int, []byte, error) {
+	if len(data) > maxLineLength && bytes.IndexByte(data[:maxLineLength+1], '\n') < 0 {
+		cut := lineCut(data)
+		return cut, data[:cut], nil
+	}
+	return bufio.ScanLines(data, atEOF)
+}
@@ -0,0 +1,72 @@
+package launcher
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTailLogLongLines(t *testing.T) {
+	dir := t.TempDir()
+	SetLogDir(dir)
+	defer SetLogDir("")
+
+	long := strings.Repeat("x", MaxLogSize+1) + strings.Repeat("é", maxLineLength) // Past any scanner buffer
+	content := "first\n" + long + "\nlast\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "app.log"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := TailLog("app", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) < 3 || lines[0] != "first" || lines[len(lines)-1] != "last" {
+		t.Fatalf("got %d lines, first %.20q, last %.20q", len(lines), lines[0], lines[len(lines)-1])
+	}
+	pieces := lines[1 : len(lines)-1]
+	for i, piece := range pieces {
+		if len(piece) > maxLineLength || !utf8.ValidString(piece) {
+			t.Errorf("piece %d: %d bytes, valid UTF-8 %v", i, len(piece), utf8.ValidString(piece))
+		}
+	}
+	if strings.Join(pieces, "") != long {
+		t.Error("pieces do not add up to the long line")
+	}
+
+	if lines, err := TailLog("app", 2); err != nil || len(lines) != 2 || lines[1] != "last" {
+		t.Errorf("tail 2: got %d lines, %v", len(lines), err)
+	}
+}
+
+func TestRotatingLogWriteErrors(t *testing.T) {
+	l := &rotatingLog{path: filepath.Join(t.TempDir(), "app.log")}
+	if err := l.open(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { l.file.Close() }()
+
+	// A failing log file does not stop the live stream sharing its writer
+	l.file.Close()
+	var stream bytes.Buffer
+	tee := io.MultiWriter(l, &stream)
+	if n, err := tee.Write([]byte("lost\n")); n != 5 || err != nil {
+		t.Errorf("write to a closed file: %d, %v", n, err)
+	}
+	if stream.String() != "lost\n" || l.failed == nil {
+		t.Errorf("stream %q, recorded error %v", stream.String(), l.failed)
+	}
+
+	// After a failed rotation the next write reopens the file
+	l.file = nil
+	if n, err := tee.Write([]byte("kept\n")); n != 5 || err != nil || l.failed != nil {
+		t.Errorf("write after reopening: %d, %v, recorded error %v", n, err, l.failed)
+	}
+	if data, _ := os.ReadFile(l.path); string(data) != "kept\n" {
+		t.Errorf("log file: %q", data)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package launcher
+
+import "runtime"
+
+// defaultOpener uses open on macOS and xdg-open on other desktops
+func defaultOpener() Opener {
+	if runtime.GOOS == "darwin" {
+		return CommandOpener{Name: "open"}
+	}
+	return CommandOpener{Name: "xdg-open"}
+}
@@ -0,0 +1,15 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultOpener uses the shell's file protocol handler, which opens documents
+// and URLs alike with the application registered for them
+func defaultOpener() Opener {
+	return CommandOpener{
+		Name: filepath.Join(os.Getenv("SystemRoot"), "System32", "rundll32.exe"),
+		Args: []string{"url.dll,FileProtocolHandler"},
+	}
+}
@@ -0,0 +1,81 @@
+package launcher
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// DefaultStopTimeout is how long a stop waits for a graceful exit before killing
+const DefaultStopTimeout = 10 * time.Second
+
+// StopResult reports how the processes of an app were terminated
+type StopResult struct {
+	PIDs   []int `json:"pids"`   // Every process that was asked to stop
+	Forced []int `json:"forced"` // Processes that had to be killed
+}
+
+// StopProcesses asks each process to close gracefully and kills those still
+// running after timeout. Processes with no graceful channel (e.g. a Windows
+// console app without a window) are killed straight away.
+func StopProcesses(pids []int, timeout time.Duration) (StopResult, error) {
+	result := StopResult{PIDs: pids, Forced: []int{}}
+	markStopRequested(pids)
+
+	var closing []int
+	for _, pid := range pids {
+		if requestClose(pid) {
+			closing = append(closing, pid)
+			continue
+		}
+		if err := forceKill(pid); err != nil {
+			log.Printf("[Launcher] Failed to kill PID %d: %v", pid, err)
+		}
+		result.Forced = append(result.Forced, pid)
+	}
+
+	// Kill whatever ignored the close request
+	for _, pid := range waitForExit(closing, timeout) {
+		fmt.Printf("[Launcher] PID %d did not exit within %s, killing it\n", pid, timeout)
+		if err := forceKill(pid); err != nil {
+			log.Printf("[Launcher] Failed to kill PID %d: %v", pid, err)
+		}
+		result.Forced = append(result.Forced, pid)
+	}
+
+	// Killed processes need a moment to disappear from the process table
+	if remaining := waitForExit(pids, 2*time.Second); len(remaining) > 0 {
+		return result, fmt.Errorf("processes still running after kill: %v", remaining)
+	}
+	return result, nil
+}
+
+// waitForExit polls until every process has exited or timeout elapses,
+// returning the PIDs still alive
+func waitForExit(pids []int, timeout time.Duration) []int {
+	deadline := time.Now().Add(timeout)
+	for {
+		var alive []int
+		for _, pid := range pids {
+			if isAlive(pid) {
+				alive = append(alive, pid)
+			}
+		}
+		if len(alive) == 0 || time.Now().After(deadline) {
+			return alive
+		}
+		pids = alive
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// forceKill terminates a process immediately
+func forceKill(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	defer p.Release()
+	return p.Kill()
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// requestClose sends SIGTERM, which every process can handle
+func requestClose(pid int) bool {
+	return syscall.Kill(pid, syscall.SIGTERM) == nil
+}
+
+// isAlive reports whether the process has not exited yet.
+// Zombies still answer signals, so procfs is checked when available.
+func isAlive(pid int) bool {
+	if err := syscall.Kill(pid, 0); err != nil && err != syscall.EPERM {
+		return false
+	}
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true // No procfs on this platform, trust the signal check
+	}
+	// The state field follows the parenthesised comm
+	if end := bytes.LastIndexByte(stat, ')'); end >= 0 && end+2 < len(stat) {
+		return stat[end+2] != 'Z'
+	}
+	return true
+}
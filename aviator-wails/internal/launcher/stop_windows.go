@@ -0,0 +1,78 @@
+package launcher
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                       = syscall.NewLazyDLL("user32.dll")
+	procEnumWindows              = user32.NewProc("EnumWindows")
+	procGetWindowThreadProcessId = user32.NewProc("GetWindowThreadProcessId")
+	procPostMessageW             = user32.NewProc("PostMessageW")
+
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procOpenProcess         = kernel32.NewProc("OpenProcess")
+	procWaitForSingleObject = kernel32.NewProc("WaitForSingleObject")
+	procCloseHandle         = kernel32.NewProc("CloseHandle")
+)
+
+const (
+	WM_CLOSE                          = 0x0010
+	SYNCHRONIZE                       = 0x00100000
+	PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+	WAIT_TIMEOUT                      = 0x00000102
+	ERROR_INVALID_PARAMETER           = 87
+)
+
+// EnumWindows callbacks cannot be released, so a single one is shared
+// and its inputs/outputs are guarded by enumMu
+var (
+	enumMu      sync.Mutex
+	enumPID     uint32
+	enumWindows []uintptr
+
+	enumWindowsCallback = syscall.NewCallback(func(hwnd uintptr, _ uintptr) uintptr {
+		var owner uint32
+		procGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&owner)))
+		if owner == enumPID {
+			enumWindows = append(enumWindows, hwnd)
+		}
+		return 1 // Continue enumeration
+	})
+)
+
+// processWindows returns the top-level windows owned by a process
+func processWindows(pid int) []uintptr {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	enumPID = uint32(pid)
+	enumWindows = nil
+	procEnumWindows.Call(enumWindowsCallback, 0)
+	return enumWindows
+}
+
+// requestClose posts WM_CLOSE to every top-level window owned by the process.
+// It returns false if the process has no window to close.
+func requestClose(pid int) bool {
+	windows := processWindows(pid)
+	for _, hwnd := range windows {
+		procPostMessageW.Call(hwnd, WM_CLOSE, 0, 0)
+	}
+	return len(windows) > 0
+}
+
+// isAlive reports whether the process has not exited yet
+func isAlive(pid int) bool {
+	handle, _, err := procOpenProcess.Call(SYNCHRONIZE|PROCESS_QUERY_LIMITED_INFORMATION, 0, uintptr(pid))
+	if handle == 0 {
+		// An unknown PID is gone, anything else (e.g. access denied) means it still exists
+		return err != syscall.Errno(ERROR_INVALID_PARAMETER)
+	}
+	defer procCloseHandle.Call(handle)
+
+	ret, _, _ := procWaitForSingleObject.Call(handle, 0)
+	return ret == WAIT_TIMEOUT
+}
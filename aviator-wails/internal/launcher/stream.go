@@ -0,0 +1,184 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"unicode/utf8"
+)
+
+// subscriberBuffer is how many lines a live subscriber may lag behind before
+// lines are dropped for it. The child process is never blocked by a slow reader.
+const subscriberBuffer = 256
+
+// maxLineLength bounds a pending line, longer ones are split so a child writing
+// without newlines cannot grow the buffer without limit
+const maxLineLength = 8192
+
+var (
+	ErrNotRunning  = errors.New("app is not running")
+	ErrNotCaptured = errors.New("output capture is disabled for this app")
+)
+
+// OutputEvent is one item of a live output stream: either a line of output
+// or a count of lines dropped because the subscriber fell behind
+type OutputEvent struct {
+	Line    string
+	Dropped int
+}
+
+// outputStream splits the output of one launch into lines and fans them out
+// to live subscribers
+type outputStream struct {
+	mu      sync.Mutex
+	partial []byte
+	head    []string // The first lines of the launch, see SubscribeLaunchOutput
+	subs    map[*Subscription]struct{}
+	closed  bool
+}
+
+// Subscription receives the live output of a launch until the process exits
+// or Close is called
+type Subscription struct {
+	events  chan OutputEvent
+	dropped int // Lines lost since the last delivered event, guarded by stream.mu
+	stream  *outputStream
+}
+
+func newOutputStream() *outputStream {
+	return &outputStream{subs: make(map[*Subscription]struct{})}
+}
+
+// Write never blocks on subscribers: lines that do not fit in a
+// subscriber's buffer are counted and reported as dropped instead
+func (o *outputStream) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.partial = append(o.partial, p...)
+	for {
+		i := bytes.IndexByte(o.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(o.partial[:i], "\r"))
+		o.partial = o.partial[i+1:]
+		o.publish(line)
+	}
+	for len(o.partial) > maxLineLength {
+		cut := lineCut(o.partial)
+		o.publish(string(o.partial[:cut]))
+		o.partial = o.partial[cut:]
+	}
+	return len(p), nil
+}
+
+// lineCut returns where to split a line longer than maxLineLength: at
+// maxLineLength, moved back to a rune boundary so the pieces stay valid UTF-8
+func lineCut(line []byte) int {
+	cut := maxLineLength
+	for cut > maxLineLength-utf8.UTFMax && !utf8.RuneStart(line[cut]) {
+		cut--
+	}
+	return cut
+}
+
+// publish delivers a line to every subscriber; o.mu must be held
+func (o *outputStream) publish(line string) {
+	if len(o.head) < subscriberBuffer {
+		o.head = append(o.head, line)
+	}
+	for sub := range o.subs {
+		if sub.dropped > 0 {
+			select {
+			case sub.events <- OutputEvent{Dropped: sub.dropped}:
+				sub.dropped = 0
+			default:
+				sub.dropped++
+				continue
+			}
+		}
+		select {
+		case sub.events <- OutputEvent{Line: line}:
+		default:
+			sub.dropped++
+		}
+	}
+}
+
+// close flushes a trailing partial line and ends every subscription
+func (o *outputStream) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.partial) > 0 {
+		o.publish(string(o.partial))
+		o.partial = nil
+	}
+	for sub := range o.subs {
+		close(sub.events)
+	}
+	o.subs = nil
+	o.closed = true
+}
+
+// SubscribeOutput attaches a live reader to the output of the running launch of an app
+func SubscribeOutput(appID string) (*Subscription, error) {
+	return subscribe(appID, 0)
+}
+
+// SubscribeLaunchOutput attaches a reader to the launch with the given PID,
+// starting from the first lines it wrote so none are missed right after a launch
+func SubscribeLaunchOutput(appID string, pid int) (*Subscription, error) {
+	return subscribe(appID, pid)
+}
+
+// subscribe attaches to the running launch, replaying its head when pid names it
+func subscribe(appID string, pid int) (*Subscription, error) {
+	processMutex.RLock()
+	info, exists := runningProcesses[appID]
+	processMutex.RUnlock()
+
+	if !exists || !info.IsRunning || pid != 0 && info.Pid != pid {
+		return nil, ErrNotRunning
+	}
+	if info.stream == nil {
+		return nil, ErrNotCaptured
+	}
+
+	o := info.stream
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return nil, ErrNotRunning
+	}
+
+	sub := &Subscription{
+		events: make(chan OutputEvent, subscriberBuffer),
+		stream: o,
+	}
+	if pid != 0 {
+		for _, line := range o.head { // Fits, head is no longer than the buffer
+			sub.events <- OutputEvent{Line: line}
+		}
+	}
+	o.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Events returns the channel of output events, closed when the process exits
+func (s *Subscription) Events() <-chan OutputEvent {
+	return s.events
+}
+
+// Close detaches the subscription
+func (s *Subscription) Close() {
+	o := s.stream
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.subs[s]; ok {
+		delete(o.subs, s)
+		close(s.events)
+	}
+}
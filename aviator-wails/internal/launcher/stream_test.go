@@ -0,0 +1,78 @@
+package launcher
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestOutputStreamLines(t *testing.T) {
+	o := newOutputStream()
+	o.Write([]byte("first\r\nsec"))
+	o.Write([]byte("ond\n\nthird"))
+	o.close()
+
+	want := []string{"first", "second", "", "third"}
+	if strings.Join(o.head, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", o.head, want)
+	}
+}
+
+func TestOutputStreamLongLine(t *testing.T) {
+	o := newOutputStream()
+
+	// Written in small pieces without a newline, as a progress bar would
+	chunk := strings.Repeat("é", 100) + "x" // Odd length, so pieces end mid rune
+	total := 0
+	for total < 3*maxLineLength {
+		o.Write([]byte(chunk))
+		total += len(chunk)
+		if len(o.partial) > maxLineLength {
+			t.Fatalf("pending line grew to %d bytes", len(o.partial))
+		}
+	}
+	o.Write([]byte("end\n"))
+	o.close()
+
+	if len(o.head) < 3 {
+		t.Fatalf("got %d lines, want the long line split", len(o.head))
+	}
+	joined := 0
+	for i, line := range o.head {
+		if len(line) > maxLineLength {
+			t.Errorf("line %d has %d bytes", i, len(line))
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d was cut inside a rune", i)
+		}
+		joined += len(line)
+	}
+	if joined != total+len("end") {
+		t.Errorf("got %d bytes back, want %d", joined, total+len("end"))
+	}
+}
+
+func TestOutputStreamPowerOfTwoChunks(t *testing.T) {
+	for _, size := range []int{1024, 4096, maxLineLength, 2 * maxLineLength} {
+		o := newOutputStream()
+		chunk := []byte(strings.Repeat("a", size))
+		for i := 0; i < 4; i++ {
+			o.Write(chunk) // Lands exactly on maxLineLength, without a newline
+			if len(o.partial) > maxLineLength {
+				t.Fatalf("%d byte chunks: pending line grew to %d bytes", size, len(o.partial))
+			}
+		}
+		o.close()
+
+		total := 0
+		for _, line := range o.head {
+			if len(line) > maxLineLength {
+				t.Errorf("%d byte chunks: line of %d bytes", size, len(line))
+			}
+			total += len(line)
+		}
+		if total != 4*size {
+			t.Errorf("%d byte chunks: got %d bytes back, want %d", size, total, 4*size)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package processmon
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Process is a single entry of the host process table
+type Process struct {
+	PID       int
+	ParentPID int
+	ExeName   string    // Executable filename, e.g. "notepad.exe"
+	ExePath   string    // Full image path, empty if it could not be resolved
+	CmdLine   string    // Full command line, empty if it could not be read
+	Comm      string    // Linux process name, cut to commMaxLen characters by the kernel; empty elsewhere
+	StartTime time.Time // Zero if unknown
+
+	CPUTime     time.Duration // User + kernel time consumed so far, zero if unknown
+	MemoryBytes uint64        // Working set (resident set on Linux), zero if unknown
+}
+
+// ProcessStats are the resource metrics of one process matched to an app
+type ProcessStats struct {
+	PID         int       `json:"pid"`
+	StartTime   time.Time `json:"start_time"`
+	Uptime      float64   `json:"uptime_seconds"` // Zero if the start time is unknown
+	CPUPercent  float64   `json:"cpu_percent"`    // Share of the whole machine since the previous scan
+	MemoryBytes uint64    `json:"memory_bytes"`
+}
+
+// AppStatus is the detailed status of a watched app as of the last scan
+type AppStatus struct {
+	Running     bool           `json:"running"`
+	Instances   int            `json:"instances"`
+	PIDs        []int          `json:"pids"`
+	Uptime      float64        `json:"uptime_seconds"` // Of the oldest process
+	CPUPercent  float64        `json:"cpu_percent"`    // Sum over all processes
+	MemoryBytes uint64         `json:"memory_bytes"`   // Sum over all processes
+	Processes   []ProcessStats `json:"processes"`
+}
+
+// cpuSample is the CPU time of a process at the time of a scan
+type cpuSample struct {
+	start time.Time // Distinguishes a recycled PID
+	cpu   time.Duration
+	at    time.Time
+}
+
+// Enumerator lists the processes running on the host.
+// Each platform provides its own implementation; tests can use an in-memory table.
+type Enumerator interface {
+	Processes() ([]Process, error)
+}
+
+// Watch describes which processes belong to a watched app.
+// A process belongs to the app if it matches the configured executable
+// or any of the optional extra rules.
+type Watch struct {
+	ExePath  string // Configured executable, matched against the full image path
+	NameOnly bool   // Match on the executable filename only (legacy behaviour)
+
+	// With ExePath an interpreter: its processes only match when their command
+	// line also names this script, so other scripts it runs do not count
+	ScriptPath string
+
+	ProcessNames     []string // Alternate executable filenames, e.g. "game.exe"
+	PathGlobs        []string // filepath.Match patterns against the full image path
+	CmdLineRegex     string   // Regular expression against the full command line
+	ChildrenOfLaunch bool     // Any descendant of the PID started by Aviator
+}
+
+// watchRule is a Watch normalized for comparison
+type watchRule struct {
+	exePath  string // Resolved and cleaned, see normalizePath
+	exeName  string // Filename, see foldCase
+	nameOnly bool
+	script   string // See foldCase
+
+	altNames         []string // See foldCase
+	pathGlobs        []string // See normalizePath
+	cmdLine          *regexp.Regexp
+	childrenOfLaunch bool
+}
+
+// launchRoot is a process started by Aviator for an app
+type launchRoot struct {
+	pid     int
+	started time.Time
+}
+
+// Event is a started/stopped transition of a watched app seen by Update
+type Event struct {
+	AppID   string    `json:"app_id"`
+	Running bool      `json:"running"`
+	PIDs    []int     `json:"pids,omitempty"` // Matching PIDs after the transition, empty when stopped
+	Time    time.Time `json:"time"`
+}
+
+// eventBuffer is how many transitions a subscriber may fall behind before
+// further ones are dropped for it
+const eventBuffer = 64
+
+// startTimeSlack absorbs the clock granularity between the launcher and the OS process table
+const startTimeSlack = 2 * time.Second
+
+// commMaxLen is the length Linux truncates a process name (comm) to (TASK_COMM_LEN - 1)
+const commMaxLen = 15
+
+// ProcessMonitor monitors running processes
+type ProcessMonitor struct {
+	enumerator       Enumerator
+	watchedProcesses map[string]watchRule  // appID -> match rule
+	launches         map[string]launchRoot // appID -> last process started by Aviator
+	runningStatus    map[string]bool       // appID -> isRunning
+	runningPIDs      map[string][]int      // appID -> matching PIDs from the last scan
+	processStats     map[int]ProcessStats  // PID -> metrics of matching processes from the last scan
+	cpuSamples       map[int]cpuSample     // PID -> CPU time at the last scan, for CPU%
+	subscribers      map[chan Event]struct{}
+	mu               sync.RWMutex
+	updateMu         sync.Mutex // Serializes scans so transitions are published once and in order
+}
+
+// NewProcessMonitor creates a new process monitor using the platform enumerator
+func NewProcessMonitor() *ProcessMonitor {
+	return NewProcessMonitorWithEnumerator(newSystemEnumerator())
+}
+
+// NewProcessMonitorWithEnumerator creates a process monitor backed by a custom enumerator
+func NewProcessMonitorWithEnumerator(e Enumerator) *ProcessMonitor {
+	return &ProcessMonitor{
+		enumerator:       e,
+		watchedProcesses: make(map[string]watchRule),
+		launches:         make(map[string]launchRoot),
+		runningStatus:    make(map[string]bool),
+		runningPIDs:      make(map[string][]int),
+		processStats:     make(map[int]ProcessStats),
+		cpuSamples:       make(map[int]cpuSample),
+		subscribers:      make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving every started/stopped transition and
+// a function that ends the subscription. Transitions are never blocked on a
+// slow reader: once its buffer is full further events are dropped for it.
+func (pm *ProcessMonitor) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBuffer)
+
+	pm.mu.Lock()
+	pm.subscribers[ch] = struct{}{}
+	pm.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			pm.mu.Lock()
+			delete(pm.subscribers, ch)
+			pm.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// AddWatch adds an application to watch, replacing any previous watch for appID.
+// An invalid command-line regex is reported but the remaining rules are still installed.
+func (pm *ProcessMonitor) AddWatch(appID string, w Watch) error {
+	rule := watchRule{
+		exePath:          normalizePath(resolvePath(w.ExePath)),
+		exeName:          foldCase(filepath.Base(w.ExePath)),
+		nameOnly:         w.NameOnly,
+		script:           foldCase(w.ScriptPath),
+		childrenOfLaunch: w.ChildrenOfLaunch,
+	}
+	for _, name := range w.ProcessNames {
+		if name = strings.TrimSpace(name); name != "" {
+			rule.altNames = append(rule.altNames, foldCase(name))
+		}
+	}
+	for _, glob := range w.PathGlobs {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			rule.pathGlobs = append(rule.pathGlobs, normalizePath(glob))
+		}
+	}
+
+	var err error
+	if w.CmdLineRegex != "" {
+		rule.cmdLine, err = regexp.Compile("(?i)" + w.CmdLineRegex)
+		if err != nil {
+			err = fmt.Errorf("invalid command line pattern for app %s: %w", appID, err)
+		}
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.watchedProcesses[appID] = rule
+
+	return err
+}
+
+// RemoveWatch removes an application from watching
+func (pm *ProcessMonitor) RemoveWatch(appID string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	delete(pm.watchedProcesses, appID)
+	delete(pm.launches, appID)
+	delete(pm.runningStatus, appID)
+	delete(pm.runningPIDs, appID)
+}
+
+// TrackLaunch records the PID Aviator started for an app,
+// used by watches that follow the children of a launch
+func (pm *ProcessMonitor) TrackLaunch(appID string, pid int) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.launches[appID] = launchRoot{pid: pid, started: time.Now()}
+}
+
+// Update scans all running processes and updates status
+func (pm *ProcessMonitor) Update() error {
+	pm.updateMu.Lock()
+	defer pm.updateMu.Unlock()
+
+	// 1. Get watched apps snapshot (read-only lock)
+	pm.mu.RLock()
+	watched := make(map[string]watchRule)
+	for id, rule := range pm.watchedProcesses {
+		watched[id] = rule
+	}
+	launches := make(map[string]launchRoot)
+	for id, root := range pm.launches {
+		launches[id] = root
+	}
+	pm.mu.RUnlock()
+
+	// 2. Scan processes (NO LOCK held here, expensive operation)
+	procs, err := pm.enumerator.Processes()
+	if err != nil {
+		return err
+	}
+
+	// 3. Update status (Write lock, very fast)
+	matched := matchProcesses(watched, launches, procs)
+	currentRunning := make(map[string]bool)
+	for appID, pids := range matched {
+		currentRunning[appID] = len(pids) > 0
+	}
+
+	now := time.Now()
+	stats, samples := measureProcesses(matched, procs, pm.cpuSamples, now) // cpuSamples is only used by Update
+
+	var events []Event
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	for appID, running := range currentRunning {
+		if _, stillWatched := pm.watchedProcesses[appID]; !stillWatched {
+			continue // Removed while scanning
+		}
+		if running != pm.runningStatus[appID] {
+			events = append(events, Event{AppID: appID, Running: running, PIDs: append([]int(nil), matched[appID]...), Time: now})
+		}
+	}
+	pm.runningStatus = currentRunning
+	pm.runningPIDs = matched
+	pm.processStats = stats
+	pm.cpuSamples = samples
+
+	for _, e := range events {
+		for ch := range pm.subscribers {
+			select {
+			case ch <- e:
+			default: // Subscriber is not keeping up
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetStatus returns the running status of a specific app
+func (pm *ProcessMonitor) GetStatus(appID string) bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.runningStatus[appID]
+}
+
+// GetPIDs returns the PIDs of the processes matching an app in the last scan
+func (pm *ProcessMonitor) GetPIDs(appID string) []int {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return append([]int(nil), pm.runningPIDs[appID]...)
+}
+
+// GetAllStatuses returns the running status of all watched apps
+func (pm *ProcessMonitor) GetAllStatuses() map[string]bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	result := make(map[string]bool)
+	for appID, status := range pm.runningStatus {
+		result[appID] = status
+	}
+	return result
+}
+
+// GetAppStatus returns the detailed status of an app as of the last scan
+func (pm *ProcessMonitor) GetAppStatus(appID string) (AppStatus, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	if _, ok := pm.watchedProcesses[appID]; !ok {
+		return AppStatus{}, false
+	}
+	return pm.appStatus(appID, time.Now()), true
+}
+
+// GetAllAppStatuses returns the detailed status of all watched apps
+func (pm *ProcessMonitor) GetAllAppStatuses() map[string]AppStatus {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	now := time.Now()
+	result := make(map[string]AppStatus)
+	for appID := range pm.watchedProcesses {
+		result[appID] = pm.appStatus(appID, now)
+	}
+	return result
+}
+
+// appStatus aggregates the stats of an app's processes, the caller holds the lock
+func (pm *ProcessMonitor) appStatus(appID string, now time.Time) AppStatus {
+	status := AppStatus{
+		PIDs:      append([]int{}, pm.runningPIDs[appID]...),
+		Processes: []ProcessStats{},
+	}
+	status.Instances = len(status.PIDs)
+	status.Running = status.Instances > 0
+
+	for _, pid := range status.PIDs {
+		ps := pm.processStats[pid]
+		if !ps.StartTime.IsZero() {
+			ps.Uptime = now.Sub(ps.StartTime).Seconds()
+		}
+		status.Uptime = max(status.Uptime, ps.Uptime)
+		status.CPUPercent += ps.CPUPercent
+		status.MemoryBytes += ps.MemoryBytes
+		status.Processes = append(status.Processes, ps)
+	}
+	return status
+}
+
+// measureProcesses builds the stats of every matched process. CPU% is the CPU
+// time used since the previous sample divided by the elapsed time on all cores.
+func measureProcesses(matched map[string][]int, procs []Process, prev map[int]cpuSample, now time.Time) (map[int]ProcessStats, map[int]cpuSample) {
+	wanted := make(map[int]bool)
+	for _, pids := range matched {
+		for _, pid := range pids {
+			wanted[pid] = true
+		}
+	}
+
+	cores := float64(runtime.NumCPU())
+	stats := make(map[int]ProcessStats)
+	samples := make(map[int]cpuSample)
+	for _, p := range procs {
+		if !wanted[p.PID] {
+			continue
+		}
+
+		ps := ProcessStats{PID: p.PID, StartTime: p.StartTime, MemoryBytes: p.MemoryBytes}
+		sample := cpuSample{start: p.StartTime, cpu: p.CPUTime, at: now}
+		if last, ok := prev[p.PID]; ok && last.start.Equal(p.StartTime) && p.CPUTime >= last.cpu {
+			if elapsed := now.Sub(last.at); elapsed > 0 {
+				ps.CPUPercent = 100 * float64(p.CPUTime-last.cpu) / float64(elapsed) / cores
+			}
+		}
+		stats[p.PID] = ps
+		samples[p.PID] = sample
+	}
+	return stats, samples
+}
+
+// matchProcesses returns the PIDs of the processes belonging to every watched app
+func matchProcesses(watched map[string]watchRule, launches map[string]launchRoot, procs []Process) map[string][]int {
+	matched := make(map[string][]int)
+	for id := range watched {
+		matched[id] = nil
+	}
+
+	// Descendant sets are only built for apps that ask for them
+	descendants := make(map[string]map[int]bool)
+	for appID, rule := range watched {
+		if root, ok := launches[appID]; ok && rule.childrenOfLaunch {
+			descendants[appID] = launchTree(root, procs)
+		}
+	}
+
+	for _, p := range procs {
+		exeName := foldCase(p.ExeName)
+		exePath := normalizePath(p.ExePath)
+
+		// Check if this process matches any watched app
+		for appID, rule := range watched {
+			if rule.matches(exeName, exePath, foldCase(p.Comm), p.CmdLine) || descendants[appID][p.PID] {
+				matched[appID] = append(matched[appID], p.PID)
+			}
+		}
+	}
+	return matched
+}
+
+// matches reports whether a process with the given normalized name and path belongs to the rule.
+// Processes whose image path could not be read (e.g. elevated ones) fall back to the name,
+// and on Linux to comm.
+func (r watchRule) matches(exeName, exePath, comm, cmdLine string) bool {
+	isExe := exePath == r.exePath
+	if r.nameOnly || exePath == "" || r.exePath == "" {
+		isExe = exeName == r.exeName || (exePath == "" && commMatches(comm, r.exeName))
+	}
+	if isExe && (r.script == "" || strings.Contains(foldCase(cmdLine), r.script)) {
+		return true
+	}
+
+	for _, name := range r.altNames {
+		if exeName == name || (exePath == "" && commMatches(comm, name)) {
+			return true
+		}
+	}
+
+	if exePath != "" {
+		for _, glob := range r.pathGlobs {
+			if ok, _ := filepath.Match(glob, exePath); ok {
+				return true
+			}
+		}
+	}
+
+	return r.cmdLine != nil && cmdLine != "" && r.cmdLine.MatchString(cmdLine)
+}
+
+// commMatches reports whether comm names the executable name.
+// comm is truncated by the kernel, so a commMaxLen character comm also matches a longer name.
+func commMatches(comm, name string) bool {
+	if comm == "" {
+		return false
+	}
+	return comm == name || (len(comm) == commMaxLen && strings.HasPrefix(name, comm))
+}
+
+// launchTree returns the launched PID and all its live descendants.
+// Processes older than the launch are skipped so a recycled PID is not adopted.
+func launchTree(root launchRoot, procs []Process) map[int]bool {
+	earliest := root.started.Add(-startTimeSlack)
+	startedAfterLaunch := func(p Process) bool {
+		return p.StartTime.IsZero() || !p.StartTime.Before(earliest)
+	}
+
+	children := make(map[int][]Process)
+	for _, p := range procs {
+		if p.PID != p.ParentPID {
+			children[p.ParentPID] = append(children[p.ParentPID], p)
+		}
+	}
+
+	tree := make(map[int]bool)
+	for _, p := range procs {
+		if p.PID != root.pid {
+			continue
+		}
+		if !startedAfterLaunch(p) {
+			return tree // The PID now belongs to an unrelated process
+		}
+		tree[p.PID] = true
+	}
+
+	// The launcher itself may already have exited, its children keep its PID as parent
+	queue := []int{root.pid}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		for _, child := range children[pid] {
+			if tree[child.PID] || !startedAfterLaunch(child) {
+				continue
+			}
+			tree[child.PID] = true
+			queue = append(queue, child.PID)
+		}
+	}
+	return tree
+}
+
+// resolvePath makes a configured path absolute and follows symlinks so it
+// compares equal to the image path reported by the OS
+func resolvePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path
+}
+
+// normalizePath cleans a path for comparison, see foldCase
+func normalizePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return foldCase(filepath.Clean(path))
+}
+
+// foldCase lowercases file names and paths where the file system ignores case.
+// Elsewhere /opt/App and /opt/app are different files.
+func foldCase(name string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToLower(name)
+	}
+	return name
+}
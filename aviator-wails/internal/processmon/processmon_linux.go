@@ -0,0 +1,190 @@
+package processmon
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultProcRoot is where procfs is mounted on a standard Linux host
+	DefaultProcRoot = "/proc"
+
+	// clockTicks is USER_HZ, the unit of the starttime field in /proc/<pid>/stat.
+	// It is 100 on every architecture Linux supports in practice.
+	clockTicks = 100
+)
+
+// pageSize converts the rss field of /proc/<pid>/stat (in pages) to bytes
+var pageSize = uint64(os.Getpagesize())
+
+// ProcfsEnumerator lists processes by scanning a procfs tree
+type ProcfsEnumerator struct {
+	Root string // procfs mount point, overridable for tests
+}
+
+func newSystemEnumerator() Enumerator {
+	return ProcfsEnumerator{Root: DefaultProcRoot}
+}
+
+// NewProcessMonitorWithRoot creates a process monitor that scans the given
+// procfs root instead of /proc (e.g. a fake tree built by tests)
+func NewProcessMonitorWithRoot(procRoot string) *ProcessMonitor {
+	return NewProcessMonitorWithEnumerator(ProcfsEnumerator{Root: procRoot})
+}
+
+// Processes reads every numeric directory of the procfs root
+func (e ProcfsEnumerator) Processes() ([]Process, error) {
+	entries, err := os.ReadDir(e.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	bootTime := readBootTime(e.Root)
+
+	var procs []Process
+	for _, entry := range entries {
+		// Only numeric directories are processes
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		p, ok := readProcess(filepath.Join(e.Root, entry.Name()), pid, bootTime)
+		if !ok {
+			continue // Process exited while scanning
+		}
+		procs = append(procs, p)
+	}
+
+	return procs, nil
+}
+
+// readProcess builds a Process from /proc/<pid>.
+// The executable name is taken from the exe link, then the first cmdline
+// argument, then comm: exe is unreadable for other users' processes and
+// kernel threads have no cmdline. comm is kept as well since a process
+// may rewrite its cmdline, see commMatches.
+func readProcess(procDir string, pid int, bootTime time.Time) (Process, bool) {
+	p := Process{PID: pid}
+
+	stat, err := os.ReadFile(filepath.Join(procDir, "stat"))
+	if err != nil {
+		return p, false
+	}
+	st, err := parseStat(stat)
+	if err != nil {
+		return p, false
+	}
+	p.ParentPID = st.ppid
+	p.Comm = st.comm
+	if !bootTime.IsZero() {
+		p.StartTime = bootTime.Add(ticksToDuration(st.startTicks))
+	}
+	p.CPUTime = ticksToDuration(st.utime + st.stime)
+	p.MemoryBytes = st.rssPages * pageSize
+
+	// Arguments are NUL separated (and terminated)
+	var argv0 []byte
+	if cmdline, err := os.ReadFile(filepath.Join(procDir, "cmdline")); err == nil {
+		cmdline = bytes.TrimRight(cmdline, "\x00")
+		argv0 = cmdline
+		if i := bytes.IndexByte(cmdline, 0); i >= 0 {
+			argv0 = cmdline[:i]
+		}
+		p.CmdLine = string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))
+	}
+
+	switch target, err := os.Readlink(filepath.Join(procDir, "exe")); {
+	case err == nil:
+		// A replaced binary shows up as "/path/to/exe (deleted)"
+		p.ExePath = strings.TrimSuffix(target, " (deleted)")
+		p.ExeName = filepath.Base(p.ExePath)
+	case len(argv0) > 0:
+		p.ExeName = filepath.Base(string(argv0))
+	default:
+		p.ExeName = st.comm
+	}
+
+	return p, true
+}
+
+// statFields are the parts of /proc/<pid>/stat the monitor uses
+type statFields struct {
+	comm         string
+	ppid         int
+	utime, stime uint64 // CPU time in clock ticks
+	startTicks   uint64 // Clock ticks since boot
+	rssPages     uint64
+}
+
+// parseStat extracts the fields used by the monitor from the contents of /proc/<pid>/stat.
+// comm is wrapped in parentheses and may itself contain spaces or ')'.
+func parseStat(stat []byte) (statFields, error) {
+	var st statFields
+	open := bytes.IndexByte(stat, '(')
+	end := bytes.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return st, fmt.Errorf("malformed stat line")
+	}
+	st.comm = string(stat[open+1 : end])
+
+	// Fields after comm start at field 3 (state)
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return st, fmt.Errorf("short stat line")
+	}
+
+	var err error
+	if st.ppid, err = strconv.Atoi(fields[1]); err != nil { // field 4
+		return st, err
+	}
+	numbers := []struct {
+		dst   *uint64
+		index int
+	}{
+		{&st.utime, 11},      // field 14
+		{&st.stime, 12},      // field 15
+		{&st.startTicks, 19}, // field 22
+		{&st.rssPages, 21},   // field 24
+	}
+	for _, n := range numbers {
+		if *n.dst, err = strconv.ParseUint(fields[n.index], 10, 64); err != nil {
+			return st, err
+		}
+	}
+
+	return st, nil
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / clockTicks
+}
+
+// readBootTime reads the btime line of /proc/stat, zero if unavailable
+func readBootTime(procRoot string) time.Time {
+	f, err := os.Open(filepath.Join(procRoot, "stat"))
+	if err != nil {
+		return time.Time{}
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "btime ") {
+			continue
+		}
+		secs, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "btime ")), 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+		return time.Unix(secs, 0)
+	}
+	return time.Time{}
+}
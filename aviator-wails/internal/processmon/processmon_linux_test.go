@@ -0,0 +1,190 @@
+package processmon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeProc describes one /proc/<pid> directory of a fake procfs tree
+type fakeProc struct {
+	pid, ppid  int
+	comm       string
+	cmdline    string // NUL separated, empty for no cmdline file content
+	exe        string // Target of the exe link, empty for an unreadable link
+	utime      uint64
+	stime      uint64
+	startTicks uint64
+	rssPages   uint64
+}
+
+// statLine formats a /proc/<pid>/stat line with the fields parseStat reads
+func statLine(p fakeProc) string {
+	return fmt.Sprintf("%d (%s) S %d 1 1 0 -1 4194304 0 0 0 0 %d %d 0 0 20 0 1 0 %d 0 %d 18446744073709551615\n",
+		p.pid, p.comm, p.ppid, p.utime, p.stime, p.startTicks, p.rssPages)
+}
+
+// writeProcfs builds a procfs tree in a temp dir, btime 0 leaves out /proc/stat
+func writeProcfs(t *testing.T, btime int64, procs ...fakeProc) string {
+	t.Helper()
+	root := t.TempDir()
+	if btime != 0 {
+		stat := fmt.Sprintf("cpu  1 2 3 4\nintr 12345\nbtime %d\nprocesses 42\n", btime)
+		if err := os.WriteFile(filepath.Join(root, "stat"), []byte(stat), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Non-process entries are ignored
+	if err := os.Mkdir(filepath.Join(root, "sys"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range procs {
+		dir := filepath.Join(root, fmt.Sprint(p.pid))
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(statLine(p)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cmdline"), []byte(p.cmdline), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if p.exe != "" {
+			if err := os.Symlink(p.exe, filepath.Join(dir, "exe")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return root
+}
+
+func TestParseStat(t *testing.T) {
+	p := fakeProc{pid: 42, ppid: 7, comm: "evil) S 1 (x", utime: 150, stime: 50, startTicks: 12345, rssPages: 300}
+	st, err := parseStat([]byte(statLine(p)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := statFields{comm: "evil) S 1 (x", ppid: 7, utime: 150, stime: 50, startTicks: 12345, rssPages: 300}
+	if st != want {
+		t.Errorf("got %+v, want %+v", st, want)
+	}
+
+	for _, bad := range []string{"", "42 no parens", "42 (comm) S 1 2 3", "42 (comm) S x 1 1 0 -1 0 0 0 0 0 1 1 0 0 20 0 1 0 1 0 1"} {
+		if _, err := parseStat([]byte(bad)); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestProcfsEnumerator(t *testing.T) {
+	const btime = 1700000000
+	root := writeProcfs(t, btime,
+		fakeProc{pid: 100, ppid: 1, comm: "server", cmdline: "/opt/app/server\x00--port\x008080\x00", exe: "/opt/app/server", utime: 200, stime: 100, startTicks: 500, rssPages: 10},
+		fakeProc{pid: 101, ppid: 100, comm: "worker", cmdline: "/opt/app/worker\x00", exe: "/opt/app/worker (deleted)"},
+		fakeProc{pid: 102, ppid: 1, comm: "other-user", cmdline: "/usr/bin/python3\x00script.py\x00"}, // exe unreadable
+		fakeProc{pid: 103, ppid: 2, comm: "kworker/0:1"},                                              // kernel thread, no cmdline
+	)
+
+	procs, err := ProcfsEnumerator{Root: root}.Processes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPID := make(map[int]Process)
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+	if len(byPID) != 4 {
+		t.Fatalf("got %d processes, want 4: %+v", len(byPID), procs)
+	}
+
+	server := byPID[100]
+	if server.ExePath != "/opt/app/server" || server.ExeName != "server" || server.ParentPID != 1 {
+		t.Errorf("server: %+v", server)
+	}
+	if server.CmdLine != "/opt/app/server --port 8080" {
+		t.Errorf("server cmdline: %q", server.CmdLine)
+	}
+	if want := time.Unix(btime, 0).Add(5 * time.Second); !server.StartTime.Equal(want) {
+		t.Errorf("server start: got %v, want %v", server.StartTime, want)
+	}
+	if server.CPUTime != 3*time.Second {
+		t.Errorf("server cpu: %v", server.CPUTime)
+	}
+	if server.MemoryBytes != 10*pageSize {
+		t.Errorf("server memory: %d", server.MemoryBytes)
+	}
+
+	if worker := byPID[101]; worker.ExePath != "/opt/app/worker" || worker.ExeName != "worker" {
+		t.Errorf("deleted exe: %+v", worker)
+	}
+	if script := byPID[102]; script.ExePath != "" || script.ExeName != "python3" || script.CmdLine != "/usr/bin/python3 script.py" {
+		t.Errorf("cmdline fallback: %+v", script)
+	}
+	if kthread := byPID[103]; kthread.ExePath != "" || kthread.ExeName != "kworker/0:1" || kthread.CmdLine != "" {
+		t.Errorf("comm fallback: %+v", kthread)
+	}
+}
+
+func TestReadBootTime(t *testing.T) {
+	if got := readBootTime(writeProcfs(t, 1700000000)); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("got %v", got)
+	}
+	if got := readBootTime(writeProcfs(t, 0)); !got.IsZero() {
+		t.Errorf("missing /proc/stat: got %v", got)
+	}
+
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "stat"), []byte("cpu 1 2 3\nbtime soon\n"), 0644)
+	if got := readBootTime(root); !got.IsZero() {
+		t.Errorf("malformed btime: got %v", got)
+	}
+
+	// Without a boot time processes still list, with an unknown start
+	root = writeProcfs(t, 0, fakeProc{pid: 1, comm: "init", cmdline: "/sbin/init\x00", startTicks: 100})
+	procs, err := ProcfsEnumerator{Root: root}.Processes()
+	if err != nil || len(procs) != 1 || !procs[0].StartTime.IsZero() {
+		t.Errorf("got %+v, %v", procs, err)
+	}
+}
+
+func TestMonitorWithProcfsRoot(t *testing.T) {
+	root := writeProcfs(t, 1700000000,
+		fakeProc{pid: 100, ppid: 1, comm: "server", cmdline: "/opt/app/server\x00", exe: "/opt/app/server"},
+		fakeProc{pid: 200, ppid: 1, comm: "server", cmdline: "/srv/other/server\x00", exe: "/srv/other/server"},
+	)
+
+	pm := NewProcessMonitorWithRoot(root)
+	pm.AddWatch("app", Watch{ExePath: "/opt/app/server"})
+	if err := pm.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if pids := pm.GetPIDs("app"); len(pids) != 1 || pids[0] != 100 {
+		t.Errorf("got %v, want [100]", pids)
+	}
+}
+
+func TestMonitorTruncatedComm(t *testing.T) {
+	const exe = "/opt/app/very-long-server-name"
+	root := writeProcfs(t, 0,
+		fakeProc{pid: 100, ppid: 1, comm: "very-long-serve"},                                            // Other user's process without cmdline
+		fakeProc{pid: 101, ppid: 1, comm: "very-long-serve", cmdline: "server: idle\x00"},               // Rewrote its cmdline
+		fakeProc{pid: 102, ppid: 1, comm: "very-long-serve", exe: "/srv/other/very-long-server-name-2"}, // exe readable, comm ignored
+		fakeProc{pid: 103, ppid: 1, comm: "very-long"},                                                  // Not truncated, a different name
+		fakeProc{pid: 104, ppid: 1, comm: "helper-process-"},                                            // Alternate name, truncated
+	)
+
+	pm := NewProcessMonitorWithRoot(root)
+	pm.AddWatch("app", Watch{ExePath: exe, ProcessNames: []string{"helper-process-name"}})
+	if err := pm.Update(); err != nil {
+		t.Fatal(err)
+	}
+	pids := pm.GetPIDs("app")
+	slices.Sort(pids)
+	if !slices.Equal(pids, []int{100, 101, 104}) {
+		t.Errorf("got %v, want [100 101 104]", pids)
+	}
+}
@@ -0,0 +1,316 @@
+package processmon
+
+import (
+	"errors"
+	"maps"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTable is an in-memory process table
+type fakeTable struct {
+	mu    sync.Mutex
+	procs []Process
+	err   error
+}
+
+func (f *fakeTable) Processes() ([]Process, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.procs), f.err
+}
+
+func (f *fakeTable) set(procs ...Process) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.procs = procs
+}
+
+// scan runs one Update and returns the sorted PIDs matched to appID
+func scan(t *testing.T, pm *ProcessMonitor, appID string) []int {
+	t.Helper()
+	if err := pm.Update(); err != nil {
+		t.Fatal(err)
+	}
+	pids := pm.GetPIDs(appID)
+	slices.Sort(pids)
+	return pids
+}
+
+func proc(pid int, path string) Process {
+	return Process{PID: pid, ParentPID: 1, ExeName: filepath.Base(path), ExePath: path}
+}
+
+func TestUpdateUsesEnumerator(t *testing.T) {
+	table := &fakeTable{}
+	table.set(proc(10, "server"), proc(11, "client"))
+
+	pm := NewProcessMonitorWithEnumerator(table)
+	pm.AddWatch("server", Watch{ExePath: "server", NameOnly: true})
+	pm.AddWatch("editor", Watch{ExePath: "editor", NameOnly: true})
+
+	if got := scan(t, pm, "server"); !slices.Equal(got, []int{10}) {
+		t.Errorf("server: got %v, want [10]", got)
+	}
+	if want := map[string]bool{"server": true, "editor": false}; !maps.Equal(pm.GetAllStatuses(), want) {
+		t.Errorf("statuses: got %v, want %v", pm.GetAllStatuses(), want)
+	}
+
+	// A failed scan keeps the last state
+	table.set()
+	table.err = errors.New("access denied")
+	if err := pm.Update(); err == nil {
+		t.Error("expected the enumerator error")
+	}
+	if !pm.GetStatus("server") {
+		t.Error("failed scan: server no longer running")
+	}
+
+	table.err = nil
+	if got := scan(t, pm, "server"); len(got) != 0 || pm.GetStatus("server") {
+		t.Errorf("after exit: got %v, running %v", got, pm.GetStatus("server"))
+	}
+
+	// Removed watches are no longer reported
+	pm.RemoveWatch("editor")
+	if _, ok := pm.GetAllStatuses()["editor"]; ok {
+		t.Error("removed watch still reported")
+	}
+}
+
+func TestMatchRules(t *testing.T) {
+	dir := t.TempDir()
+	table := &fakeTable{}
+	table.set(
+		proc(20, filepath.Join(dir, "launcher.exe")),
+		proc(21, filepath.Join(dir, "bin", "gameclient.exe")),                                         // Alternate name
+		proc(22, filepath.Join(dir, "versions", "1.2", "game-x64.exe")),                               // Glob
+		proc(23, filepath.Join(dir, "versions", "1.2", "tools", "game-x64.exe")),                      // Glob does not cross folders
+		Process{PID: 24, ParentPID: 1, ExeName: "java", CmdLine: "java -jar Server.jar --world main"}, // Regex
+		Process{PID: 25, ParentPID: 1, ExeName: "java", CmdLine: "java -jar other.jar"},
+	)
+
+	pm := NewProcessMonitorWithEnumerator(table)
+	err := pm.AddWatch("app", Watch{
+		ExePath:      filepath.Join(dir, "launcher.exe"),
+		ProcessNames: []string{" gameclient.exe ", ""},
+		PathGlobs:    []string{filepath.Join(dir, "versions", "*", "game-*.exe")},
+		CmdLineRegex: `server\.jar`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scan(t, pm, "app"); !slices.Equal(got, []int{20, 21, 22, 24}) {
+		t.Errorf("got %v, want [20 21 22 24]", got)
+	}
+
+	// A bad regex is reported, the other rules still apply
+	err = pm.AddWatch("app", Watch{ExePath: filepath.Join(dir, "launcher.exe"), CmdLineRegex: "server("})
+	if err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+	if got := scan(t, pm, "app"); !slices.Equal(got, []int{20}) {
+		t.Errorf("after a bad regex: got %v, want [20]", got)
+	}
+}
+
+func TestMatchScript(t *testing.T) {
+	dir := t.TempDir()
+	python := filepath.Join(dir, "bin", "python3")
+	script := filepath.Join(dir, "scripts", "bot.py")
+	other := filepath.Join(dir, "scripts", "other.py")
+
+	withCmd := func(p Process, cmd string) Process {
+		p.CmdLine = cmd
+		return p
+	}
+	table := &fakeTable{}
+	table.set(
+		withCmd(proc(50, python), python+" -u "+script+" --verbose"),
+		withCmd(proc(51, python), python+" "+other),
+		withCmd(proc(52, filepath.Join(dir, "bin", "editor")), "editor "+script), // Names the script, not the interpreter
+		proc(53, python), // Command line unreadable
+	)
+
+	pm := NewProcessMonitorWithEnumerator(table)
+	pm.AddWatch("bot", Watch{ExePath: python, ScriptPath: script})
+	if got := scan(t, pm, "bot"); !slices.Equal(got, []int{50}) {
+		t.Errorf("got %v, want [50]", got)
+	}
+}
+
+func TestMatchPathVersusName(t *testing.T) {
+	dir := t.TempDir()
+	game := filepath.Join(dir, "games", "game.exe")
+	table := &fakeTable{}
+	table.set(
+		proc(10, game),
+		proc(11, filepath.Join(dir, "copy", "game.exe")),      // Same name, other folder
+		Process{PID: 12, ParentPID: 1, ExeName: "game.exe"},   // Image path unreadable, e.g. elevated
+		proc(13, filepath.Join(dir, "games", "launcher.exe")), // Same folder, other name
+	)
+
+	pm := NewProcessMonitorWithEnumerator(table)
+	pm.AddWatch("path", Watch{ExePath: game})
+	pm.AddWatch("name", Watch{ExePath: game, NameOnly: true})
+
+	if got := scan(t, pm, "path"); !slices.Equal(got, []int{10, 12}) {
+		t.Errorf("path watch: got %v, want [10 12]", got)
+	}
+	if got := pm.GetPIDs("name"); len(got) != 3 {
+		t.Errorf("name-only watch: got %v, want 10, 11 and 12", got)
+	}
+	if !pm.GetStatus("path") || pm.GetStatus("unknown") {
+		t.Errorf("statuses: %v", pm.GetAllStatuses())
+	}
+}
+
+func TestMatchCase(t *testing.T) {
+	dir := t.TempDir()
+	table := &fakeTable{}
+	table.set(
+		proc(30, filepath.Join(dir, "App", "run")),
+		proc(31, filepath.Join(dir, "app", "run")),
+		proc(32, filepath.Join(dir, "app", "RUN")),
+	)
+
+	pm := NewProcessMonitorWithEnumerator(table)
+	pm.AddWatch("path", Watch{ExePath: filepath.Join(dir, "app", "run")})
+	pm.AddWatch("name", Watch{ExePath: filepath.Join(dir, "app", "run"), NameOnly: true})
+
+	wantPath, wantName := []int{31}, []int{30, 31}
+	if runtime.GOOS == "windows" {
+		// The file system ignores case
+		wantPath, wantName = []int{30, 31, 32}, []int{30, 31, 32}
+	}
+	if got := scan(t, pm, "path"); !slices.Equal(got, wantPath) {
+		t.Errorf("path watch: got %v, want %v", got, wantPath)
+	}
+	if got := scan(t, pm, "name"); !slices.Equal(got, wantName) {
+		t.Errorf("name-only watch: got %v, want %v", got, wantName)
+	}
+}
+
+func TestLaunchTree(t *testing.T) {
+	launched := time.Now()
+	fresh := launched.Add(time.Second)
+	old := launched.Add(-time.Hour)
+
+	procs := []Process{
+		{PID: 100, ParentPID: 1, StartTime: launched},
+		{PID: 101, ParentPID: 100, StartTime: fresh},
+		{PID: 102, ParentPID: 101, StartTime: fresh},
+		{PID: 103, ParentPID: 100},                 // Start time unknown
+		{PID: 104, ParentPID: 100, StartTime: old}, // Older than the launch, its parent PID was recycled
+		{PID: 200, ParentPID: 1, StartTime: fresh}, // Unrelated
+		{PID: 201, ParentPID: 200, StartTime: fresh},
+	}
+	root := launchRoot{pid: 100, started: launched}
+
+	tree := launchTree(root, procs)
+	for _, pid := range []int{100, 101, 102, 103} {
+		if !tree[pid] {
+			t.Errorf("%d is missing from the tree", pid)
+		}
+	}
+	if len(tree) != 4 {
+		t.Errorf("got %v, want 100 to 103", tree)
+	}
+
+	// The launched process exited, its children still count
+	if tree := launchTree(root, procs[1:]); !tree[101] || !tree[102] || tree[100] {
+		t.Errorf("launcher exited: got %v", tree)
+	}
+
+	// The launched PID now belongs to an older, unrelated process
+	recycled := []Process{
+		{PID: 100, ParentPID: 1, StartTime: old},
+		{PID: 101, ParentPID: 100, StartTime: old},
+	}
+	if tree := launchTree(root, recycled); len(tree) != 0 {
+		t.Errorf("recycled PID: got %v, want none", tree)
+	}
+
+	// Through the monitor, only watches asking for children use the tree
+	table := &fakeTable{}
+	table.set(
+		Process{PID: 300, ParentPID: 1, ExeName: "setup.exe", StartTime: time.Now()},
+		Process{PID: 301, ParentPID: 300, ExeName: "worker.exe", StartTime: time.Now()},
+	)
+	pm := NewProcessMonitorWithEnumerator(table)
+	pm.AddWatch("children", Watch{ExePath: "setup.exe", NameOnly: true, ChildrenOfLaunch: true})
+	pm.AddWatch("plain", Watch{ExePath: "setup.exe", NameOnly: true})
+	pm.TrackLaunch("children", 300)
+	pm.TrackLaunch("plain", 300)
+	if got := scan(t, pm, "children"); !slices.Equal(got, []int{300, 301}) {
+		t.Errorf("children watch: got %v, want [300 301]", got)
+	}
+	if got := pm.GetPIDs("plain"); !slices.Equal(got, []int{300}) {
+		t.Errorf("plain watch: got %v, want [300]", got)
+	}
+}
+
+func TestUpdateEvents(t *testing.T) {
+	table := &fakeTable{}
+	pm := NewProcessMonitorWithEnumerator(table)
+	pm.AddWatch("app", Watch{ExePath: "server", NameOnly: true})
+
+	events, unsubscribe := pm.Subscribe()
+	defer unsubscribe()
+
+	next := func() (Event, bool) {
+		select {
+		case e := <-events:
+			return e, true
+		default:
+			return Event{}, false
+		}
+	}
+
+	scan(t, pm, "app")
+	if e, ok := next(); ok {
+		t.Fatalf("stopped app: unexpected %+v", e)
+	}
+
+	table.set(proc(40, "server"), proc(41, "server"))
+	scan(t, pm, "app")
+	e, ok := next()
+	if !ok || e.AppID != "app" || !e.Running || !slices.Equal(e.PIDs, []int{40, 41}) {
+		t.Fatalf("start: got %+v, %v", e, ok)
+	}
+
+	// Still running, even with fewer processes, is not a transition
+	table.set(proc(41, "server"))
+	scan(t, pm, "app")
+	if e, ok := next(); ok {
+		t.Fatalf("still running: unexpected %+v", e)
+	}
+
+	table.set()
+	scan(t, pm, "app")
+	if e, ok := next(); !ok || e.Running || len(e.PIDs) != 0 {
+		t.Fatalf("stop: got %+v, %v", e, ok)
+	}
+
+	// A failed scan keeps the last state
+	table.set(proc(42, "server"))
+	table.err = errors.New("access denied")
+	if err := pm.Update(); err == nil {
+		t.Error("expected the enumerator error")
+	}
+	if e, ok := next(); ok || pm.GetStatus("app") {
+		t.Errorf("failed scan: got %+v, running %v", e, pm.GetStatus("app"))
+	}
+
+	// Ended subscriptions get nothing more
+	unsubscribe()
+	table.err = nil
+	scan(t, pm, "app")
+	if _, open := <-events; open {
+		t.Error("channel still open after unsubscribe")
+	}
+}
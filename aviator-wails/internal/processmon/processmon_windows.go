@@ -1,24 +1,34 @@
 package processmon
 
 import (
-	"path/filepath"
-	"strings"
-	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
 var (
-	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
-	procCreateToolhelp32Snapshot = kernel32.NewProc("CreateToolhelp32Snapshot")
-	procProcess32First           = kernel32.NewProc("Process32FirstW")
-	procProcess32Next            = kernel32.NewProc("Process32NextW")
-	procCloseHandle              = kernel32.NewProc("CloseHandle")
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procCreateToolhelp32Snapshot   = kernel32.NewProc("CreateToolhelp32Snapshot")
+	procProcess32First             = kernel32.NewProc("Process32FirstW")
+	procProcess32Next              = kernel32.NewProc("Process32NextW")
+	procCloseHandle                = kernel32.NewProc("CloseHandle")
+	procOpenProcess                = kernel32.NewProc("OpenProcess")
+	procQueryFullProcessImageNameW = kernel32.NewProc("QueryFullProcessImageNameW")
+	procGetProcessTimes            = kernel32.NewProc("GetProcessTimes")
+	procGetProcessMemoryInfo       = kernel32.NewProc("K32GetProcessMemoryInfo")
+
+	ntdll                         = syscall.NewLazyDLL("ntdll.dll")
+	procNtQueryInformationProcess = ntdll.NewProc("NtQueryInformationProcess")
 )
 
 const (
-	TH32CS_SNAPPROCESS = 0x00000002
-	MAX_PATH           = 260
+	TH32CS_SNAPPROCESS                = 0x00000002
+	MAX_PATH                          = 260
+	PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+
+	// ProcessCommandLineInformation (Windows 8.1+) returns a UNICODE_STRING
+	// followed by its buffer, readable with limited query rights
+	processCommandLineInformation = 60
 )
 
 type PROCESSENTRY32 struct {
@@ -34,86 +44,56 @@ type PROCESSENTRY32 struct {
 	szExeFile           [MAX_PATH]uint16
 }
 
-// ProcessMonitor monitors running processes
-type ProcessMonitor struct {
-	watchedProcesses map[string]string // appID -> exe filename (lowercase)
-	runningStatus    map[string]bool   // appID -> isRunning
-	mu               sync.RWMutex
-}
-
-// NewProcessMonitor creates a new process monitor
-func NewProcessMonitor() *ProcessMonitor {
-	return &ProcessMonitor{
-		watchedProcesses: make(map[string]string),
-		runningStatus:    make(map[string]bool),
-	}
-}
-
-// AddWatch adds an application to watch
-func (pm *ProcessMonitor) AddWatch(appID, exePath string) {
-	pm.mu.Lock()
-	defer pm.mu.Unlock()
-
-	// Extract just the executable filename (case-insensitive)
-	exeName := filepath.Base(exePath)
-	pm.watchedProcesses[appID] = strings.ToLower(exeName)
+// PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
 }
 
-// RemoveWatch removes an application from watching
-func (pm *ProcessMonitor) RemoveWatch(appID string) {
-	pm.mu.Lock()
-	defer pm.mu.Unlock()
+// toolhelpEnumerator lists processes with a Toolhelp32 snapshot
+type toolhelpEnumerator struct{}
 
-	delete(pm.watchedProcesses, appID)
-	delete(pm.runningStatus, appID)
+func newSystemEnumerator() Enumerator {
+	return toolhelpEnumerator{}
 }
 
-// Update scans all running processes and updates status
-func (pm *ProcessMonitor) Update() error {
-	// 1. Get watched apps snapshot (read-only lock)
-	pm.mu.RLock()
-	watched := make(map[string]string) // appID -> exeName
-	for id, exe := range pm.watchedProcesses {
-		watched[id] = exe
-	}
-	pm.mu.RUnlock()
-
-	// 2. Scan processes (NO LOCK held here, expensive operation)
+// Processes walks a Toolhelp32 snapshot of the process table
+func (toolhelpEnumerator) Processes() ([]Process, error) {
 	snapshot, _, _ := procCreateToolhelp32Snapshot.Call(
 		uintptr(TH32CS_SNAPPROCESS),
 		0,
 	)
 	if snapshot == 0 || snapshot == uintptr(syscall.InvalidHandle) {
-		return syscall.GetLastError()
+		return nil, syscall.GetLastError()
 	}
 	defer procCloseHandle.Call(snapshot)
 
 	var pe PROCESSENTRY32
 	pe.dwSize = uint32(unsafe.Sizeof(pe))
 
-	// Local map for results
-	currentRunning := make(map[string]bool)
-	for id := range watched {
-		currentRunning[id] = false
-	}
-
 	// Get first process
 	ret, _, _ := procProcess32First.Call(snapshot, uintptr(unsafe.Pointer(&pe)))
 	if ret == 0 {
-		return syscall.GetLastError()
+		return nil, syscall.GetLastError()
 	}
 
-	// Iterate through processes
+	var procs []Process
 	for {
-		exeName := syscall.UTF16ToString(pe.szExeFile[:])
-		exeNameLower := strings.ToLower(exeName)
-
-		// Check if this process matches any watched app
-		for appID, watchedExe := range watched {
-			if exeNameLower == watchedExe {
-				currentRunning[appID] = true
-			}
+		p := Process{
+			PID:       int(pe.th32ProcessID),
+			ParentPID: int(pe.th32ParentProcessID),
+			ExeName:   syscall.UTF16ToString(pe.szExeFile[:]),
 		}
+		queryProcessDetails(&p)
+		procs = append(procs, p)
 
 		// Get next process
 		ret, _, _ := procProcess32Next.Call(snapshot, uintptr(unsafe.Pointer(&pe)))
@@ -122,29 +102,84 @@ func (pm *ProcessMonitor) Update() error {
 		}
 	}
 
-	// 3. Update status (Write lock, very fast)
-	pm.mu.Lock()
-	defer pm.mu.Unlock()
-	pm.runningStatus = currentRunning
+	return procs, nil
+}
+
+// queryProcessDetails fills in the full image path, command line, creation time,
+// CPU time and working set of a process.
+// All are best-effort: protected and system processes refuse to be opened.
+func queryProcessDetails(p *Process) {
+	if p.PID == 0 {
+		return // System Idle Process
+	}
+
+	handle, _, _ := procOpenProcess.Call(PROCESS_QUERY_LIMITED_INFORMATION, 0, uintptr(p.PID))
+	if handle == 0 {
+		return
+	}
+	defer procCloseHandle.Call(handle)
+
+	var buf [4 * MAX_PATH]uint16
+	size := uint32(len(buf))
+	ret, _, _ := procQueryFullProcessImageNameW.Call(handle, 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if ret != 0 {
+		p.ExePath = syscall.UTF16ToString(buf[:size])
+	}
+
+	var creation, exit, kernel, user syscall.Filetime
+	ret, _, _ = procGetProcessTimes.Call(
+		handle,
+		uintptr(unsafe.Pointer(&creation)),
+		uintptr(unsafe.Pointer(&exit)),
+		uintptr(unsafe.Pointer(&kernel)),
+		uintptr(unsafe.Pointer(&user)),
+	)
+	if ret != 0 {
+		p.StartTime = time.Unix(0, creation.Nanoseconds())
+		p.CPUTime = filetimeDuration(kernel) + filetimeDuration(user)
+	}
+
+	var mem processMemoryCounters
+	mem.cb = uint32(unsafe.Sizeof(mem))
+	ret, _, _ = procGetProcessMemoryInfo.Call(handle, uintptr(unsafe.Pointer(&mem)), uintptr(mem.cb))
+	if ret != 0 {
+		p.MemoryBytes = uint64(mem.WorkingSetSize)
+	}
+
+	p.CmdLine = queryCommandLine(handle)
+}
 
-	return nil
+// filetimeDuration converts a FILETIME holding an amount of time (100ns units), not a date
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration(int64(ft.HighDateTime)<<32|int64(ft.LowDateTime)) * 100
 }
 
-// GetStatus returns the running status of a specific app
-func (pm *ProcessMonitor) GetStatus(appID string) bool {
-	pm.mu.RLock()
-	defer pm.mu.RUnlock()
-	return pm.runningStatus[appID]
+// unicodeString mirrors the NT UNICODE_STRING header
+type unicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *uint16
 }
 
-// GetAllStatuses returns the running status of all watched apps
-func (pm *ProcessMonitor) GetAllStatuses() map[string]bool {
-	pm.mu.RLock()
-	defer pm.mu.RUnlock()
+// queryCommandLine reads the command line of an opened process, empty on failure
+func queryCommandLine(handle uintptr) string {
+	var size uint32
+	// The first call fails with STATUS_INFO_LENGTH_MISMATCH and reports the size
+	procNtQueryInformationProcess.Call(handle, processCommandLineInformation, 0, 0, uintptr(unsafe.Pointer(&size)))
+	if size < uint32(unsafe.Sizeof(unicodeString{})) {
+		return ""
+	}
+
+	// Allocate as []uintptr so the UNICODE_STRING header is pointer-aligned
+	buf := make([]uintptr, (size+uint32(unsafe.Sizeof(uintptr(0)))-1)/uint32(unsafe.Sizeof(uintptr(0))))
+	status, _, _ := procNtQueryInformationProcess.Call(handle, processCommandLineInformation, uintptr(unsafe.Pointer(&buf[0])), uintptr(size), uintptr(unsafe.Pointer(&size)))
+	if status != 0 {
+		return ""
+	}
 
-	result := make(map[string]bool)
-	for appID, status := range pm.runningStatus {
-		result[appID] = status
+	us := (*unicodeString)(unsafe.Pointer(&buf[0]))
+	if us.Buffer == nil || us.Length == 0 {
+		return ""
 	}
-	return result
+	return syscall.UTF16ToString(unsafe.Slice(us.Buffer, us.Length/2))
 }
@@ -0,0 +1,190 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr is a parsed five-field cron expression: minute hour day-of-month month day-of-week.
+// Each field accepts *, numbers, names (jan, mon), ranges (1-5), lists (1,15) and steps (*/10, 8-18/2).
+// As in Vixie cron, when both day fields are restricted a day matching either one is due,
+// a field starting with * (e.g. */2) counting as unrestricted.
+type Expr struct {
+	minute, hour, dom, month, dow uint64 // Bit n set if value n matches
+	domAny, dowAny                bool   // The day field starts with *, it defers to the other one
+	hourAny                       bool   // The hour field starts with *, see Matches
+}
+
+// macros are the @ shortcuts understood in place of the five fields
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Parse parses a cron expression or one of the @daily style macros
+func Parse(spec string) (*Expr, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := macros[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression needs 5 fields (minute hour day month weekday), got %d", len(fields))
+	}
+
+	var e Expr
+	var err error
+	if e.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if e.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if e.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if e.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if e.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if e.dow&(1<<7) != 0 {
+		e.dow |= 1 // 7 is Sunday too
+	}
+	e.domAny = strings.HasPrefix(fields[2], "*")
+	e.dowAny = strings.HasPrefix(fields[4], "*")
+	e.hourAny = strings.HasPrefix(fields[1], "*")
+
+	return &e, nil
+}
+
+// parseField turns one comma separated field into a bitset of the values in [min, max]
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseValue(from, names); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = parseValue(to, names); err != nil {
+					return 0, err
+				}
+			case !hasStep:
+				hi = lo // "5/15" means from 5 to the end, every 15
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<v) != 0
+}
+
+// dayMatches applies the day-of-month / day-of-week rule
+func (e *Expr) dayMatches(t time.Time) bool {
+	dom := has(e.dom, t.Day())
+	dow := has(e.dow, int(t.Weekday()))
+	if e.domAny || e.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Matches reports whether the expression is due in the minute containing t.
+// As in Vixie cron, when a daylight saving change repeats an hour, expressions
+// with a fixed hour are only due in its first pass.
+func (e *Expr) Matches(t time.Time) bool {
+	return has(e.month, int(t.Month())) && e.dayMatches(t) && has(e.hour, t.Hour()) && has(e.minute, t.Minute()) &&
+		(e.hourAny || !repeatedWallTime(t))
+}
+
+// repeatedWallTime reports whether the clock already showed t's time an hour earlier
+func repeatedWallTime(t time.Time) bool {
+	earlier := t.Add(-time.Hour)
+	return earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
+
+// Next returns the first minute after t when the expression is due, see Matches,
+// zero if there is none within five years (e.g. "0 0 30 2 *"). Times skipped
+// by a daylight saving change are not due.
+func (e *Expr) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		var next time.Time
+		switch {
+		case !has(e.month, int(t.Month())):
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !e.dayMatches(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !has(e.hour, t.Hour()):
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !has(e.minute, t.Minute()) || !e.Matches(t):
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+		// Daylight saving changes can map the next hour back onto the current one
+		if !next.After(t) {
+			next = t.Add(time.Hour)
+		}
+		t = next
+	}
+	return time.Time{}
+}
@@ -0,0 +1,156 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // The DST cases need America/New_York on any machine
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"1,,2 * * * *",
+		"* * * foo *",
+		"@often",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) accepted", spec)
+		}
+	}
+}
+
+func TestParseMacros(t *testing.T) {
+	tests := map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		" @HOURLY ": "0 * * * *",
+	}
+	for macro, spec := range tests {
+		got, err := Parse(macro)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", macro, err)
+			continue
+		}
+		want, _ := Parse(spec)
+		if *got != *want {
+			t.Errorf("Parse(%q) = %+v, want %+v", macro, got, want)
+		}
+	}
+}
+
+func TestMatchesDays(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	// September 2023: the 1st is a Friday, the 13th a Wednesday
+	tests := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		// Both day fields restricted: either one
+		{"0 0 13 * fri", day(2023, 9, 13), true},
+		{"0 0 13 * fri", day(2023, 9, 15), true},
+		{"0 0 13 * fri", day(2023, 9, 14), false},
+		// One day field starting with *: both, the * one matching its own values
+		{"0 0 */2 * mon", day(2023, 9, 11), true},  // Odd day, Monday
+		{"0 0 */2 * mon", day(2023, 9, 18), false}, // Even day
+		{"0 0 */2 * mon", day(2023, 9, 13), false}, // Not a Monday
+		{"0 0 1 * */2", day(2023, 9, 1), false},    // Friday is day 5, not in 0,2,4,6
+		{"0 0 1 * */2", day(2023, 10, 1), true},    // Sunday
+		{"0 0 * * *", day(2023, 9, 14), true},
+		// Sunday is 0 and 7
+		{"0 0 * * 7", day(2023, 9, 17), true},
+		{"0 0 * * 0", day(2023, 9, 17), true},
+		{"0 0 * * 5-7", day(2023, 9, 16), true},
+		{"0 0 * * 5-7", day(2023, 9, 17), true},
+		{"0 0 * * 5-7", day(2023, 9, 18), false},
+		{"0 0 * * SUN", day(2023, 9, 17), true},
+		// Months and lists
+		{"0 0 1 jan,jul *", day(2023, 7, 1), true},
+		{"0 0 1 jan,jul *", day(2023, 9, 1), false},
+		// Minute and hour
+		{"5/15 8-18/2 * * *", day(2023, 9, 1).Add(10*time.Hour + 35*time.Minute), true},
+		{"5/15 8-18/2 * * *", day(2023, 9, 1).Add(11*time.Hour + 35*time.Minute), false},
+		{"5/15 8-18/2 * * *", day(2023, 9, 1).Add(10*time.Hour + 30*time.Minute), false},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.spec, err)
+		}
+		if got := e.Matches(tt.t); got != tt.want {
+			t.Errorf("%q at %s: got %v, want %v", tt.spec, tt.t.Format("Mon Jan 2 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(loc *time.Location, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+	utc := time.UTC
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time // Zero for never
+	}{
+		{"next minute", "* * * * *", at(utc, 2024, 1, 1, 10, 0).Add(30 * time.Second), at(utc, 2024, 1, 1, 10, 1)},
+		{"strictly after", "0 10 * * *", at(utc, 2024, 1, 1, 10, 0), at(utc, 2024, 1, 2, 10, 0)},
+		{"year end", "0 0 1 * *", at(utc, 2024, 12, 31, 23, 59), at(utc, 2025, 1, 1, 0, 0)},
+		{"skips short months", "0 0 31 * *", at(utc, 2024, 1, 31, 12, 0), at(utc, 2024, 3, 31, 0, 0)},
+		{"leap day", "0 12 29 2 *", at(utc, 2025, 3, 1, 0, 0), at(utc, 2028, 2, 29, 12, 0)},
+		{"never", "0 0 30 2 *", at(utc, 2024, 1, 1, 0, 0), time.Time{}},
+		{"weekday or day", "0 0 13 * fri", at(utc, 2023, 9, 1, 12, 0), at(utc, 2023, 9, 8, 0, 0)},
+
+		// America/New_York springs forward on 2024-03-10 at 02:00 and falls back on 2024-11-03 at 02:00
+		{"daily across spring forward", "0 9 * * *", at(ny, 2024, 3, 9, 10, 0), at(ny, 2024, 3, 10, 9, 0)},
+		{"daily across fall back", "0 9 * * *", at(ny, 2024, 11, 2, 10, 0), at(ny, 2024, 11, 3, 9, 0)},
+		{"skipped time", "30 2 * * *", at(ny, 2024, 3, 9, 3, 0), at(ny, 2024, 3, 11, 2, 30)},
+		{"repeated time, first pass", "30 1 * * *", at(ny, 2024, 11, 3, 0, 0), at(ny, 2024, 11, 3, 1, 30)},
+		{"repeated time, once", "30 1 * * *", at(ny, 2024, 11, 3, 1, 30), at(ny, 2024, 11, 4, 1, 30)},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := e.Next(tt.from)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%q, %s) = %s, want %s", tt.name, tt.spec, tt.from, got, tt.want)
+		}
+	}
+
+	// Wildcard hours keep running through the repeated hour
+	e, _ := Parse("*/30 * * * *")
+	first := at(ny, 2024, 11, 3, 1, 30) // EDT
+	second := e.Next(first)
+	if second.Sub(first) != 30*time.Minute || second.Hour() != 1 || second.Minute() != 0 {
+		t.Errorf("*/30 after %s: got %s, want 01:00 EST", first, second)
+	}
+	fixed, _ := Parse("30 1 * * *")
+	if !fixed.Matches(first) || fixed.Matches(first.Add(time.Hour)) {
+		t.Errorf("30 1 * * *: due in the first pass %v, second pass %v", fixed.Matches(first), fixed.Matches(first.Add(time.Hour)))
+	}
+}
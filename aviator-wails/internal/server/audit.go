@@ -0,0 +1,90 @@
+package server
+
+import (
+	"aviator-wails/internal/audit"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RecordAudit appends an entry to the audit log, failures are only logged
+func (s *Server) RecordAudit(e audit.Entry) {
+	if err := s.Audit.Record(e); err != nil {
+		log.Printf("Error writing audit log: %v", err)
+	}
+}
+
+// auditRequest records an action triggered by an HTTP request, with the client's details
+func (s *Server) auditRequest(r *http.Request, e audit.Entry) {
+	e.Source = audit.SourceWeb
+	e.ClientIP = clientIP(r)
+	e.UserAgent = r.UserAgent()
+	if e.SessionID == "" {
+		if cookie, err := r.Cookie("aviator_key"); err == nil {
+			e.SessionID = sessionID(cookie.Value)
+		}
+	}
+	s.RecordAudit(e)
+}
+
+// sessionID identifies a session in the audit log without revealing its key,
+// which would be enough to impersonate it
+func sessionID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// handleAudit returns the audit entries after ?since= (RFC 3339 or Unix seconds),
+// at most ?limit= (default 500, max 5000) of the most recent ones
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		since, err = parseSince(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid since"})
+			return
+		}
+	}
+
+	limit := 500
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid limit"})
+			return
+		}
+		limit = min(n, 5000)
+	}
+
+	entries, err := s.Audit.Since(since, limit)
+	if err != nil {
+		log.Printf("Error reading audit log: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(entries)
+}
+
+func parseSince(v string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"aviator-wails/internal/audit"
+	"aviator-wails/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// startDependencies launches the apps an app depends on that are not running yet,
+// in dependency order, waiting for each one to be ready before the next.
+// It stops at the first dependency that fails.
+func (s *Server) startDependencies(ctx context.Context, app config.App, record func(audit.Entry)) ([]StepResult, error) {
+	deps, err := s.Config.DependencyOrder(app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []StepResult{}
+	for _, dep := range deps {
+		result := StepResult{AppID: dep.ID, AppName: dep.Name}
+		if pids := s.appPIDs(dep.ID); len(pids) > 0 {
+			result.Status, result.PIDs = StepAlreadyRunning, pids
+			results = append(results, result)
+			continue
+		}
+
+		pid, pids, err := s.startApp(dep, nil)
+		if errors.Is(err, ErrAlreadyRunning) {
+			// Started by someone else since the check above
+			result.Status, result.PIDs = StepAlreadyRunning, pids
+			results = append(results, result)
+			continue
+		}
+
+		entry := audit.Entry{Action: audit.ActionLaunch, AppID: dep.ID, AppName: dep.Name,
+			Details: map[string]interface{}{"dependency_of": app.ID}}
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Details["pid"] = pid
+		}
+		record(entry)
+
+		if err == nil {
+			pids, err = s.waitReady(ctx, dep, pid, 0)
+		}
+		if err != nil {
+			log.Printf("Dependency %s of %s failed: %v", dep.Name, app.Name, err)
+			result.Status, result.Error = StepError, err.Error()
+			results = append(results, result)
+			return results, fmt.Errorf("dependency %s: %w", dep.Name, err)
+		}
+		result.Status, result.PID, result.PIDs = StepLaunched, pid, pids
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Event types pushed on /api/events
+const (
+	EventSnapshot = "snapshot" // Full state, sent first on every connection
+	EventStatus   = "status"   // An app started or stopped
+	EventHealth   = "health"   // The health check of a running app started or stopped failing
+	EventLaunch   = "launch"   // Aviator launched an app
+	EventStop     = "stop"     // Aviator stopped an app
+	EventExit     = "exit"     // A process launched by Aviator ended
+	EventConfig   = "config"   // Apps or settings were saved
+	EventServer   = "server"   // The server is shutting down
+)
+
+// eventBuffer is how far a client may fall behind before it is disconnected.
+// Dropping single events would leave it with a wrong picture, after a
+// reconnect it receives a fresh snapshot instead.
+const eventBuffer = 64
+
+// Event is a single message of the /api/events stream
+type Event struct {
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// eventHub fans events out to the connected clients
+type eventHub struct {
+	subscribers map[chan Event]struct{}
+	mu          sync.Mutex
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[chan Event]struct{})}
+}
+
+func (h *eventHub) subscribe() chan Event {
+	ch := make(chan Event, eventBuffer)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *eventHub) unsubscribe(ch chan Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[ch]; ok {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+// publish sends an event to every client without blocking,
+// clients that are not keeping up are disconnected
+func (h *eventHub) publish(eventType string, data interface{}) {
+	ev := Event{Type: eventType, Time: time.Now(), Data: data}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// forwardMonitorEvents republishes the process monitor transitions for as long as it runs
+func (s *Server) forwardMonitorEvents() {
+	transitions, _ := s.ProcessMonitor.Subscribe()
+	for t := range transitions {
+		s.events.publish(EventStatus, t)
+	}
+}
+
+// refreshStatusSoon rescans processes shortly after a launch so clients see
+// the new status without waiting for the next periodic scan
+func (s *Server) refreshStatusSoon() {
+	time.AfterFunc(500*time.Millisecond, func() {
+		if err := s.ProcessMonitor.Update(); err != nil {
+			log.Printf("Error updating process monitor: %v", err)
+		}
+	})
+}
+
+// handleEvents streams status changes, launches, stops and config changes as Server-Sent Events
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, `{"error": "Streaming unsupported"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before taking the snapshot so no change falls in between
+	events := s.events.subscribe()
+	defer s.events.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: 3000\n\n")
+	writeEvent(w, Event{Type: EventSnapshot, Time: time.Now(), Data: map[string]interface{}{
+		"statuses": s.ProcessMonitor.GetAllStatuses(),
+		"health":   s.HealthStatuses(),
+		"server":   "running",
+	}})
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.streamCtx.Done():
+			// Deliver what was published before shutdown, e.g. the server event
+			for pending := len(events); pending > 0; pending-- {
+				writeEvent(w, <-events)
+			}
+			flusher.Flush()
+			return
+		case <-keepAlive.C:
+			fmt.Fprintf(w, ": ping\n\n")
+			flusher.Flush()
+		case ev, open := <-events:
+			if !open {
+				return // Too slow, the client reconnects and resyncs
+			}
+			writeEvent(w, ev)
+
+			// Batch whatever is already queued into a single flush
+			for pending := len(events); pending > 0; pending-- {
+				ev, open := <-events
+				if !open {
+					break
+				}
+				writeEvent(w, ev)
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", ev.Type, err)
+		return
+	}
+	writeSSEData(w, ev.Type, string(data))
+}
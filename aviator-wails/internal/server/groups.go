@@ -0,0 +1,217 @@
+package server
+
+import (
+	"aviator-wails/internal/audit"
+	"aviator-wails/internal/config"
+	"aviator-wails/internal/launcher"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// waitPollInterval is how often waitRunning rescans the process table
+const waitPollInterval = 500 * time.Millisecond
+
+// Outcomes of a group step or dependency start
+const (
+	StepLaunched       = "launched"
+	StepAlreadyRunning = "already_running"
+	StepError          = "error"
+	StepSkipped        = "skipped" // An earlier step failed or the launch was cancelled
+)
+
+// StepResult reports what a group launch or a dependency start did with one app
+type StepResult struct {
+	AppID   string `json:"app_id"`
+	AppName string `json:"app_name,omitempty"`
+	Status  string `json:"status"`
+	PID     int    `json:"pid,omitempty"`
+	PIDs    []int  `json:"pids,omitempty"` // Seen by the process monitor, when the step waited or the app was already up
+	Error   string `json:"error,omitempty"`
+}
+
+// LaunchGroup runs the steps of a group in order. Each launch is passed to record
+// for the audit log. Steps left when ctx is done are skipped.
+func (s *Server) LaunchGroup(ctx context.Context, g config.Group, record func(audit.Entry)) []StepResult {
+	results := make([]StepResult, 0, len(g.Steps))
+	abort := ""
+	for i, step := range g.Steps {
+		result := StepResult{AppID: step.AppID}
+		app, found := s.Config.GetAppByID(step.AppID)
+		result.AppName = app.Name
+
+		switch {
+		case abort != "":
+			result.Status, result.Error = StepSkipped, abort
+		case !found:
+			result.Status, result.Error = StepError, ErrAppNotFound.Error()
+		default:
+			result = s.runGroupStep(ctx, g, i, app, record)
+		}
+
+		if abort == "" && ctx.Err() != nil {
+			abort = "group launch cancelled"
+		} else if abort == "" && result.Status == StepError && !g.ContinueOnError {
+			abort = fmt.Sprintf("step %d failed", i+1)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// runGroupStep waits the step delay, launches the app and optionally waits for it to be ready
+func (s *Server) runGroupStep(ctx context.Context, g config.Group, index int, app config.App, record func(audit.Entry)) StepResult {
+	step := g.Steps[index]
+	result := StepResult{AppID: app.ID, AppName: app.Name}
+
+	if step.DelaySeconds > 0 {
+		timer := time.NewTimer(time.Duration(step.DelaySeconds) * time.Second)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Status, result.Error = StepSkipped, "group launch cancelled"
+			return result
+		case <-timer.C:
+		}
+	}
+
+	pid, pids, err := s.startApp(app, nil)
+	entry := audit.Entry{Action: audit.ActionLaunch, AppID: app.ID, AppName: app.Name,
+		Details: map[string]interface{}{"group_id": g.ID, "group": g.Name, "step": index + 1}}
+	switch {
+	case errors.Is(err, ErrAlreadyRunning):
+		result.Status, result.PIDs = StepAlreadyRunning, pids
+		entry.Error = "already running"
+		entry.Details["pids"] = pids
+	case err != nil:
+		result.Status, result.Error = StepError, err.Error()
+		entry.Error = err.Error()
+		log.Printf("Group %s: error launching %s: %v", g.Name, app.Name, err)
+	default:
+		result.Status, result.PID = StepLaunched, pid
+		entry.Details["pid"] = pid
+	}
+	record(entry)
+
+	if result.Status != StepLaunched || !step.WaitRunning {
+		return result
+	}
+
+	// The app's readiness probe decides, the step may set its own timeout
+	timeout := time.Duration(step.WaitTimeoutSeconds) * time.Second
+	pids, err = s.waitReady(ctx, app, pid, timeout)
+	if err != nil {
+		result.Status, result.Error = StepError, err.Error()
+		return result
+	}
+	result.PIDs = pids
+	return result
+}
+
+// waitRunning rescans the process table until the monitor sees the app running.
+// It fails early if the launched process exits without the app showing up.
+func (s *Server) waitRunning(ctx context.Context, appID string, launchedPID int, timeout time.Duration) ([]int, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ProcessMonitor.Update(); err != nil {
+			log.Printf("Error updating process monitor: %v", err)
+		}
+		if pids := s.ProcessMonitor.GetPIDs(appID); len(pids) > 0 {
+			return pids, nil
+		}
+		if exit, ok := launcher.GetLastExit(appID); ok && exit.PID == launchedPID {
+			return nil, fmt.Errorf("exited before it was seen running: %s", exit)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			return nil, fmt.Errorf("not running after %s", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleGroups serves /api/groups, /api/groups/{id} and POST /api/groups/{id}/launch
+func (s *Server) handleGroups(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/groups"), "/"), "/")
+
+	switch {
+	case id == "" && r.Method == "GET":
+		json.NewEncoder(w).Encode(s.Config.GetGroups())
+
+	case id == "" && r.Method == "POST":
+		var g config.Group
+		if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+			http.Error(w, `{"error": "Invalid request"}`, http.StatusBadRequest)
+			return
+		}
+		_, exists := s.Config.GetGroupByID(g.ID)
+		g, err := s.Config.SaveGroup(g)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		change := "add_group"
+		if exists {
+			change = "update_group"
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+		s.auditRequest(r, audit.Entry{Action: audit.ActionConfig, Details: map[string]interface{}{"change": change, "group_id": g.ID, "group": g.Name}})
+		json.NewEncoder(w).Encode(g)
+
+	case id != "" && action == "" && r.Method == "DELETE":
+		removed, err := s.Config.RemoveGroup(id)
+		if !removed {
+			http.Error(w, `{"error": "Group not found"}`, http.StatusNotFound)
+			return
+		}
+		s.auditRequest(r, audit.Entry{Action: audit.ActionConfig, Details: map[string]interface{}{"change": "remove_group", "group_id": id}})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "Group removed"})
+
+	case id != "" && action == "launch" && r.Method == "POST":
+		g, found := s.Config.GetGroupByID(id)
+		if !found {
+			http.Error(w, `{"error": "Group not found"}`, http.StatusNotFound)
+			return
+		}
+
+		// Abandoned if the client goes away, the steps already launched keep running
+		steps := s.LaunchGroup(r.Context(), g, func(e audit.Entry) { s.auditRequest(r, e) })
+		for i, step := range steps {
+			if step.Status == StepError || step.Status == StepSkipped {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": fmt.Sprintf("%s: step %d (%s): %s", g.Name, i+1, step.AppName, step.Error),
+					"steps": steps,
+				})
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": "Launched " + g.Name,
+			"steps":   steps,
+		})
+
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"aviator-wails/internal/config"
+	"aviator-wails/internal/launcher"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Health states of an app, as shown by the status API and the LEDs
+const (
+	HealthRunning   = "running"
+	HealthUnhealthy = "unhealthy" // Running, but its health check keeps failing
+	HealthStopped   = "stopped"
+)
+
+// HealthStatus is what the health check of an app found lately
+type HealthStatus struct {
+	State     string     `json:"state"`
+	Failures  int        `json:"failures"`        // Consecutive failed checks
+	Error     string     `json:"error,omitempty"` // Of the last failed check
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// HealthChange is passed to OnHealthChange listeners and pushed to /api/events
+type HealthChange struct {
+	AppID string `json:"app_id"`
+	HealthStatus
+}
+
+// healthState is the checker bookkeeping of one app
+type healthState struct {
+	HealthStatus
+	nextCheck time.Time
+	checking  bool
+}
+
+// healthChecker holds the results of the apps' health checks
+type healthChecker struct {
+	states    map[string]*healthState
+	listeners []func(HealthChange)
+	mu        sync.Mutex
+}
+
+func newHealthChecker() *healthChecker {
+	return &healthChecker{states: make(map[string]*healthState)}
+}
+
+// status returns the health of an app, nil if it has no health check
+func (hc *healthChecker) status(appID string) *HealthStatus {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	st, ok := hc.states[appID]
+	if !ok {
+		return nil
+	}
+	status := st.HealthStatus
+	return &status
+}
+
+// all returns the health of every app with a health check
+func (hc *healthChecker) all() map[string]HealthStatus {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	statuses := make(map[string]HealthStatus, len(hc.states))
+	for appID, st := range hc.states {
+		statuses[appID] = st.HealthStatus
+	}
+	return statuses
+}
+
+// OnHealthChange registers fn to be called every time the health state of an app changes
+func (s *Server) OnHealthChange(fn func(HealthChange)) {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+	s.health.listeners = append(s.health.listeners, fn)
+}
+
+// HealthStatuses returns the health of every app with a health check
+func (s *Server) HealthStatuses() map[string]HealthStatus {
+	return s.health.all()
+}
+
+// healthState returns the tri-state health of an app from its process status
+// and, if it has one, its health check
+func (s *Server) healthState(appID string, running bool) string {
+	if !running {
+		return HealthStopped
+	}
+	if status := s.health.status(appID); status != nil && status.State == HealthUnhealthy {
+		return HealthUnhealthy
+	}
+	return HealthRunning
+}
+
+// RunHealthChecks runs the due health checks of running apps until ctx is done
+func (s *Server) RunHealthChecks(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.scheduleHealthChecks(ctx, now)
+		}
+	}
+}
+
+// scheduleHealthChecks starts the checks that are due, each in its own goroutine
+// so a slow one does not hold up the others
+func (s *Server) scheduleHealthChecks(ctx context.Context, now time.Time) {
+	apps := s.Config.GetApps()
+	var changes []HealthChange
+
+	s.health.mu.Lock()
+	configured := make(map[string]bool)
+	for _, app := range apps {
+		if app.Health == nil {
+			continue
+		}
+		configured[app.ID] = true
+
+		st, ok := s.health.states[app.ID]
+		if !ok {
+			st = &healthState{HealthStatus: HealthStatus{State: HealthStopped}}
+			s.health.states[app.ID] = st
+		}
+
+		if len(s.appPIDs(app.ID)) == 0 {
+			if st.State != HealthStopped {
+				st.HealthStatus = HealthStatus{State: HealthStopped}
+				changes = append(changes, HealthChange{AppID: app.ID, HealthStatus: st.HealthStatus})
+			}
+			continue
+		}
+		if st.State == HealthStopped {
+			// Assumed healthy until checked, the first check waits an interval to let it start
+			st.HealthStatus = HealthStatus{State: HealthRunning}
+			st.nextCheck = now.Add(app.Health.Interval())
+			changes = append(changes, HealthChange{AppID: app.ID, HealthStatus: st.HealthStatus})
+			continue
+		}
+		if st.checking || now.Before(st.nextCheck) {
+			continue
+		}
+		st.checking = true
+		st.nextCheck = now.Add(app.Health.Interval())
+		go s.runHealthCheck(ctx, app.ID, *app.Health)
+	}
+
+	// Forget apps that were removed or lost their health check
+	for appID := range s.health.states {
+		if !configured[appID] {
+			delete(s.health.states, appID)
+		}
+	}
+	s.health.mu.Unlock()
+
+	for _, c := range changes {
+		s.notifyHealth(c)
+	}
+}
+
+// runHealthCheck runs one check and records its result
+func (s *Server) runHealthCheck(ctx context.Context, appID string, check config.HealthCheck) {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout())
+	err := healthProbe(ctx, check)
+	cancel()
+	now := time.Now()
+
+	s.health.mu.Lock()
+	st, ok := s.health.states[appID]
+	if ok {
+		st.checking = false
+	}
+	if !ok || st.State == HealthStopped {
+		s.health.mu.Unlock()
+		return // Removed or exited meanwhile
+	}
+	st.CheckedAt = &now
+	previous := st.State
+	if err == nil {
+		st.State, st.Failures, st.Error = HealthRunning, 0, ""
+	} else {
+		st.Failures++
+		st.Error = err.Error()
+		if st.Failures >= check.Failures() {
+			st.State = HealthUnhealthy
+		}
+	}
+	change := HealthChange{AppID: appID, HealthStatus: st.HealthStatus}
+	s.health.mu.Unlock()
+
+	if change.State != previous {
+		if change.State == HealthUnhealthy {
+			log.Printf("App %s is unhealthy after %d failed checks: %s", appID, change.Failures, change.Error)
+		} else {
+			log.Printf("App %s is healthy again", appID)
+		}
+		s.notifyHealth(change)
+	}
+}
+
+// healthProbe makes one attempt of a health check
+func healthProbe(ctx context.Context, check config.HealthCheck) error {
+	switch check.Type {
+	case config.HealthTCP:
+		return checkTCP(ctx, check.Address)
+	case config.HealthHTTP:
+		return checkHTTP(ctx, check.URL, func(code int) bool { return code >= 200 && code < 300 })
+	case config.HealthCommand:
+		return launcher.RunCheck(ctx, check.Command)
+	}
+	return fmt.Errorf("invalid health check type %q", check.Type)
+}
+
+// notifyHealth tells the listeners and the connected clients about a health change
+func (s *Server) notifyHealth(change HealthChange) {
+	s.health.mu.Lock()
+	listeners := append([]func(HealthChange){}, s.health.listeners...)
+	s.health.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(change)
+	}
+	s.events.publish(EventHealth, change)
+}
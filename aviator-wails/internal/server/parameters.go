@@ -0,0 +1,70 @@
+package server
+
+import (
+	"aviator-wails/internal/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// parameterInfo is a launch parameter as sent to the dashboard form
+type parameterInfo struct {
+	config.Parameter
+	Files []string `json:"files,omitempty"` // file: the allowed choices right now
+}
+
+// handleParameters lists the launch parameters of an app
+func (s *Server) handleParameters(w http.ResponseWriter, app config.App) {
+	params := []parameterInfo{}
+	for _, p := range app.Parameters {
+		info := parameterInfo{Parameter: p}
+		if p.Type == config.ParamFile {
+			info.Files = p.Files()
+		}
+		params = append(params, info)
+	}
+	json.NewEncoder(w).Encode(params)
+}
+
+// launchValues reads the optional JSON object of parameter values sent with a launch.
+// Strings and numbers are accepted, anything else is refused.
+func launchValues(r *http.Request) (map[string]string, error) {
+	var raw map[string]interface{}
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil // No body, use the defaults
+		}
+		return nil, fmt.Errorf("invalid parameter values: %w", err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[name] = v
+		case json.Number:
+			values[name] = v.String()
+		default:
+			return nil, fmt.Errorf("%s must be a string or a number", name)
+		}
+	}
+	return values, nil
+}
+
+// rememberValues keeps the parameter values of a launch for later restarts
+func (s *Server) rememberValues(appID string, values map[string]string) {
+	s.lastValuesMu.Lock()
+	defer s.lastValuesMu.Unlock()
+	s.lastValues[appID] = values
+}
+
+// lastLaunchValues returns the parameter values of the last launch of an app
+func (s *Server) lastLaunchValues(appID string) map[string]string {
+	s.lastValuesMu.Lock()
+	defer s.lastValuesMu.Unlock()
+	return s.lastValues[appID]
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"aviator-wails/internal/config"
+	"aviator-wails/internal/launcher"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// probeTimeout bounds a single TCP or HTTP attempt of a readiness probe
+const probeTimeout = 2 * time.Second
+
+// waitReady waits until a launched app passes its readiness probe, or is seen
+// running when it has none, and returns the PIDs it runs as. It fails early if
+// the launched process exits first. A zero timeout uses the probe's own.
+func (s *Server) waitReady(ctx context.Context, app config.App, launchedPID int, timeout time.Duration) ([]int, error) {
+	probe := config.Readiness{Type: config.ReadyProcess}
+	if app.Ready != nil {
+		probe = *app.Ready
+	}
+	if timeout == 0 {
+		timeout = probe.Timeout()
+	}
+
+	var err error
+	switch {
+	case probe.Type == config.ReadyProcess && app.OpenedByHandler():
+		return s.appPIDs(app.ID), nil // Handed over once the opener started, there is no process of its own to wait for
+	case probe.Type == config.ReadyProcess:
+		return s.waitRunning(ctx, app.ID, launchedPID, timeout)
+	case probe.Type == config.ReadyLog && app.OpenedByHandler():
+		return nil, fmt.Errorf("log readiness is not available for documents and URLs")
+	case probe.Type == config.ReadyLog:
+		err = s.waitLogLine(ctx, app, launchedPID, probe.Pattern, timeout)
+	default:
+		err = s.pollProbe(ctx, app, launchedPID, probe, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.appPIDs(app.ID), nil
+}
+
+// pollProbe retries a TCP or HTTP probe until it passes
+func (s *Server) pollProbe(ctx context.Context, app config.App, launchedPID int, probe config.Readiness, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := runProbe(ctx, probe)
+		if err == nil {
+			return nil
+		}
+		if exit, ok := launcher.GetLastExit(app.ID); ok && exit.PID == launchedPID && len(s.appPIDs(app.ID)) == 0 && !app.OpenedByHandler() {
+			return fmt.Errorf("exited before it was ready: %s", exit)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("not ready after %s: %v", timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// runProbe makes one attempt of a TCP or HTTP probe
+func runProbe(ctx context.Context, probe config.Readiness) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	switch probe.Type {
+	case config.ReadyTCP:
+		return checkTCP(ctx, probe.Address)
+	case config.ReadyHTTP:
+		return checkHTTP(ctx, probe.URL, func(code int) bool { return code == http.StatusOK })
+	}
+	return fmt.Errorf("invalid readiness type %q", probe.Type)
+}
+
+// checkTCP connects to address and hangs up
+func checkTCP(ctx context.Context, address string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkHTTP GETs url and fails unless ok accepts the status code
+func checkHTTP(ctx context.Context, url string, ok func(code int) bool) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if !ok(resp.StatusCode) {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// waitLogLine reads the output of the launch until a line matches pattern
+func (s *Server) waitLogLine(ctx context.Context, app config.App, launchedPID int, pattern string, timeout time.Duration) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	sub, err := launcher.SubscribeLaunchOutput(app.ID, launchedPID)
+	if errors.Is(err, launcher.ErrNotCaptured) {
+		return fmt.Errorf("log readiness needs output capture")
+	}
+	if err != nil {
+		if exit, ok := launcher.GetLastExit(app.ID); ok && exit.PID == launchedPID {
+			return fmt.Errorf("exited before it was ready: %s", exit)
+		}
+		return err
+	}
+	defer sub.Close()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("not ready after %s: no output matched %q", timeout, pattern)
+		case event, ok := <-sub.Events():
+			if !ok {
+				if exit, found := launcher.GetLastExit(app.ID); found && exit.PID == launchedPID {
+					return fmt.Errorf("exited before it was ready: %s", exit)
+				}
+				return fmt.Errorf("exited before it was ready")
+			}
+			if event.Dropped == 0 && re.MatchString(event.Line) {
+				return nil
+			}
+		}
+	}
+}
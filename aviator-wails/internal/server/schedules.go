@@ -0,0 +1,165 @@
+package server
+
+import (
+	"aviator-wails/internal/audit"
+	"aviator-wails/internal/config"
+	"aviator-wails/internal/schedule"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ScheduleStatus is a schedule as reported to the UI
+type ScheduleStatus struct {
+	config.Schedule
+	AppName string     `json:"app_name"`
+	NextRun *time.Time `json:"next_run,omitempty"` // Nil when disabled or never due
+}
+
+// Schedules returns every schedule with the next time it will run
+func (s *Server) Schedules() []ScheduleStatus {
+	now := time.Now()
+	statuses := []ScheduleStatus{}
+	for _, sc := range s.Config.GetSchedules() {
+		status := ScheduleStatus{Schedule: sc}
+		if app, found := s.Config.GetAppByID(sc.AppID); found {
+			status.AppName = app.Name
+		}
+		if expr, err := schedule.Parse(sc.Cron); err == nil && sc.Enabled {
+			if next := expr.Next(now); !next.IsZero() {
+				status.NextRun = &next
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// RunScheduler runs the due schedules at the start of every minute until ctx is done.
+// Minutes missed while the machine was asleep are not caught up.
+func (s *Server) RunScheduler(ctx context.Context) {
+	for {
+		minute := time.Now().Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(time.Until(minute))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for _, sc := range s.Config.GetSchedules() {
+			if !sc.Enabled {
+				continue
+			}
+			expr, err := schedule.Parse(sc.Cron)
+			if err != nil {
+				log.Printf("Skipping schedule %s: %v", sc.ID, err)
+				continue
+			}
+			if expr.Matches(minute) {
+				go s.runSchedule(sc) // A stop may wait for the stop timeout
+			}
+		}
+	}
+}
+
+// runSchedule performs the action of a due schedule and records the outcome
+func (s *Server) runSchedule(sc config.Schedule) {
+	app, found := s.Config.GetAppByID(sc.AppID)
+	if !found {
+		log.Printf("Schedule %s refers to a missing app %s", sc.ID, sc.AppID)
+		return
+	}
+
+	var entry audit.Entry
+	switch sc.Action {
+	case config.ScheduleLaunch:
+		pid, pids, err := s.startApp(app, nil)
+		entry = audit.Entry{Action: audit.ActionLaunch, AppID: app.ID, AppName: app.Name, Details: map[string]interface{}{}}
+		switch {
+		case errors.Is(err, ErrAlreadyRunning):
+			entry.Error = "already running"
+			entry.Details["pids"] = pids
+		case err != nil:
+			entry.Error = err.Error()
+			log.Printf("Scheduled launch of %s failed: %v", app.Name, err)
+		default:
+			entry.Details["pid"] = pid
+		}
+
+	case config.ScheduleStop:
+		result, err := s.StopApp(app.ID, 0)
+		entry = stopAuditEntry(app, result, err)
+		if entry.Details == nil {
+			entry.Details = map[string]interface{}{}
+		}
+		if err != nil && !errors.Is(err, ErrNotRunning) {
+			log.Printf("Scheduled stop of %s failed: %v", app.Name, err)
+		}
+
+	default:
+		return
+	}
+
+	entry.Source = audit.SourceScheduler
+	entry.Details["schedule_id"] = sc.ID
+	entry.Details["cron"] = sc.Cron
+	s.RecordAudit(entry)
+}
+
+// handleSchedules serves GET/POST /api/schedules and DELETE /api/schedules/{id}
+func (s *Server) handleSchedules(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/schedules"), "/")
+
+	switch {
+	case id == "" && r.Method == "GET":
+		json.NewEncoder(w).Encode(s.Schedules())
+
+	case id == "" && r.Method == "POST":
+		sc := config.Schedule{Enabled: true}
+		if err := json.NewDecoder(r.Body).Decode(&sc); err != nil {
+			http.Error(w, `{"error": "Invalid request"}`, http.StatusBadRequest)
+			return
+		}
+		sc, err := s.Config.AddSchedule(sc)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		app, _ := s.Config.GetAppByID(sc.AppID)
+		s.auditRequest(r, audit.Entry{Action: audit.ActionConfig, AppID: app.ID, AppName: app.Name,
+			Details: map[string]interface{}{"change": "add_schedule", "schedule_id": sc.ID, "cron": sc.Cron, "schedule_action": sc.Action}})
+
+		for _, status := range s.Schedules() {
+			if status.ID == sc.ID {
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(status)
+				return
+			}
+		}
+		http.Error(w, `{"error": "Schedule was saved but could not be read back"}`, http.StatusInternalServerError)
+
+	case id != "" && r.Method == "DELETE":
+		removed, err := s.Config.RemoveSchedule(id)
+		if !removed {
+			http.Error(w, `{"error": "Schedule not found"}`, http.StatusNotFound)
+			return
+		}
+		s.auditRequest(r, audit.Entry{Action: audit.ActionConfig, Details: map[string]interface{}{"change": "remove_schedule", "schedule_id": id}})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "Schedule removed"})
+
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
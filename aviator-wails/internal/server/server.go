@@ -1,15 +1,18 @@
 package server
 
 import (
+	"aviator-wails/internal/audit"
 	"aviator-wails/internal/config"
 	"aviator-wails/internal/launcher"
 	"aviator-wails/internal/processmon"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,33 +20,67 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrAppNotFound = errors.New("app not found")
+	ErrNotRunning  = errors.New("app is not running")
+
+	ErrAlreadyRunning = errors.New("app is already running")
+)
+
 type Server struct {
 	Config         *config.ConfigManager
 	ProcessMonitor *processmon.ProcessMonitor
+	Audit          *audit.Log
 	FileServer     http.Handler
 	httpServer     *http.Server
 
+	// Cancelled on shutdown to end long-lived streaming responses
+	streamCtx    context.Context
+	streamCancel context.CancelFunc
+
+	// Pushed to /api/events clients
+	events *eventHub
+
+	// Relaunches apps with a restart policy
+	supervisor *supervisor
+
+	// Results of the apps' periodic health checks
+	health *healthChecker
+
+	// Makes the single-instance check and the launch atomic
+	launchMu sync.Mutex
+
+	// Parameter values of the last launch of each app, reused by restarts
+	lastValues   map[string]map[string]string
+	lastValuesMu sync.Mutex
+
 	// Key Bucket (Session Pool)
 	keyBucket   map[string]time.Time
 	bucketMutex sync.RWMutex
-
-	// Cache for process statuses
-	statusCache      map[string]bool
-	statusCacheTime  time.Time
-	statusCacheMutex sync.RWMutex
 }
 
 func NewServer(cm *config.ConfigManager, webFS fs.FS, pm *processmon.ProcessMonitor) *Server {
 	// Create file server for static files
 	fsHandler := http.FileServer(http.FS(webFS))
 
-	return &Server{
+	s := &Server{
 		Config:         cm,
 		ProcessMonitor: pm,
+		Audit:          audit.NewLog(cm.AuditPath),
 		FileServer:     fsHandler,
-		statusCache:    make(map[string]bool),
+		events:         newEventHub(),
+		supervisor:     newSupervisor(),
+		health:         newHealthChecker(),
+		lastValues:     make(map[string]map[string]string),
 		keyBucket:      make(map[string]time.Time),
 	}
+
+	go s.forwardMonitorEvents()
+	cm.OnChange(func(kind string) {
+		s.events.publish(EventConfig, map[string]string{"kind": kind})
+	})
+	launcher.OnExit(s.handleExit)
+	return s
 }
 
 func (s *Server) Start(port int) error {
@@ -54,6 +91,10 @@ func (s *Server) Start(port int) error {
 		Addr:    addr,
 		Handler: s,
 	}
+	// Shutdown waits for active requests, so open streams must be told to end
+	s.streamCtx, s.streamCancel = context.WithCancel(context.Background())
+	s.httpServer.RegisterOnShutdown(s.streamCancel)
+	s.RecordAudit(audit.Entry{Action: audit.ActionServerStart, Source: audit.SourceSystem, Details: map[string]interface{}{"port": port}})
 
 	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return err
@@ -63,6 +104,9 @@ func (s *Server) Start(port int) error {
 
 func (s *Server) Stop() error {
 	if s.httpServer != nil {
+		s.events.publish(EventServer, map[string]string{"status": "stopping"})
+		s.RecordAudit(audit.Entry{Action: audit.ActionServerStop, Source: audit.SourceSystem})
+
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		return s.httpServer.Shutdown(ctx)
@@ -75,7 +119,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// CORS headers for dev
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "*")
 
 	// Disabilita cache per forzare aggiornamenti su mobile
@@ -131,7 +175,18 @@ func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
-		json.NewEncoder(w).Encode(s.Config.GetApps())
+		apps := s.Config.GetApps()
+		for i := range apps {
+			apps[i] = webApp(apps[i])
+		}
+		json.NewEncoder(w).Encode(apps)
+
+	case strings.HasPrefix(r.URL.Path, "/api/apps/"):
+		if !s.isAuthorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		s.handleAppAPI(w, r)
 
 	case strings.HasPrefix(r.URL.Path, "/api/launch/") && r.Method == "POST":
 		if !s.isAuthorized(r) {
@@ -139,35 +194,66 @@ func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		appID := strings.TrimPrefix(r.URL.Path, "/api/launch/")
-		s.handleLaunch(w, appID)
+		s.handleLaunch(w, r, appID)
 
-	case r.URL.Path == "/api/process-statuses" && r.Method == "GET":
+	case strings.HasPrefix(r.URL.Path, "/api/stop/") && r.Method == "POST":
 		if !s.isAuthorized(r) {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
+		appID := strings.TrimPrefix(r.URL.Path, "/api/stop/")
+		s.handleStop(w, r, appID)
 
-		s.statusCacheMutex.RLock()
-		cacheAge := time.Since(s.statusCacheTime)
-		if cacheAge < time.Second && len(s.statusCache) > 0 {
-			cached := make(map[string]bool)
-			for k, v := range s.statusCache {
-				cached[k] = v
+	case strings.HasPrefix(r.URL.Path, "/api/restart/") && r.Method == "POST":
+		if !s.isAuthorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		appID := strings.TrimPrefix(r.URL.Path, "/api/restart/")
+		s.handleRestart(w, r, appID)
+
+	case r.URL.Path == "/api/process-statuses" && r.Method == "GET":
+		if !s.isAuthorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("detail") == "1" {
+			statuses := make(map[string]appStatus)
+			for appID, status := range s.ProcessMonitor.GetAllAppStatuses() {
+				statuses[appID] = s.appStatus(appID, status)
 			}
-			s.statusCacheMutex.RUnlock()
-			json.NewEncoder(w).Encode(cached)
+			json.NewEncoder(w).Encode(statuses)
+			return
+		}
+		json.NewEncoder(w).Encode(s.ProcessMonitor.GetAllStatuses())
+
+	case r.URL.Path == "/api/schedules" || strings.HasPrefix(r.URL.Path, "/api/schedules/"):
+		if !s.isAuthorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
-		s.statusCacheMutex.RUnlock()
+		s.handleSchedules(w, r)
 
-		// Update cache
-		statuses := s.ProcessMonitor.GetAllStatuses()
-		s.statusCacheMutex.Lock()
-		s.statusCache = statuses
-		s.statusCacheTime = time.Now()
-		s.statusCacheMutex.Unlock()
+	case r.URL.Path == "/api/groups" || strings.HasPrefix(r.URL.Path, "/api/groups/"):
+		if !s.isAuthorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		s.handleGroups(w, r)
 
-		json.NewEncoder(w).Encode(statuses)
+	case r.URL.Path == "/api/audit" && r.Method == "GET":
+		if !s.isAuthorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		s.handleAudit(w, r)
+
+	case r.URL.Path == "/api/events" && r.Method == "GET":
+		if !s.isAuthorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		s.handleEvents(w, r)
 
 	case r.URL.Path == "/api/auth" && r.Method == "POST":
 		var authData struct {
@@ -184,6 +270,7 @@ func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
 			s.bucketMutex.Lock()
 			s.keyBucket[key] = time.Now()
 			s.bucketMutex.Unlock()
+			s.auditRequest(r, audit.Entry{Action: audit.ActionAuthSuccess, SessionID: sessionID(key)})
 
 			// Set HttpOnly Cookie
 			http.SetCookie(w, &http.Cookie{
@@ -199,6 +286,7 @@ func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
 				"status": "success",
 			})
 		} else {
+			s.auditRequest(r, audit.Entry{Action: audit.ActionAuthFailure, Error: "invalid PIN"})
 			http.Error(w, "Invalid PIN", http.StatusUnauthorized)
 		}
 
@@ -208,6 +296,7 @@ func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
 			s.bucketMutex.Lock()
 			delete(s.keyBucket, cookie.Value)
 			s.bucketMutex.Unlock()
+			s.auditRequest(r, audit.Entry{Action: audit.ActionLogout})
 		}
 
 		// Clear cookie
@@ -220,39 +309,20 @@ func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
 		})
 		w.WriteHeader(http.StatusOK)
 
-	case r.URL.Path == "/api/process-statuses" && r.Method == "GET":
-		if !s.isAuthorized(r) {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-		// Return cached statuses if less than 1 second old
-		s.statusCacheMutex.RLock()
-		cacheAge := time.Since(s.statusCacheTime)
-		if cacheAge < time.Second && len(s.statusCache) > 0 {
-			cached := make(map[string]bool)
-			for k, v := range s.statusCache {
-				cached[k] = v
-			}
-			s.statusCacheMutex.RUnlock()
-			json.NewEncoder(w).Encode(cached)
-			return
-		}
-		s.statusCacheMutex.RUnlock()
-
-		// Update cache
-		statuses := s.ProcessMonitor.GetAllStatuses()
-		s.statusCacheMutex.Lock()
-		s.statusCache = statuses
-		s.statusCacheTime = time.Now()
-		s.statusCacheMutex.Unlock()
-
-		json.NewEncoder(w).Encode(statuses)
-
 	default:
 		http.Error(w, "Not Found", http.StatusNotFound)
 	}
 }
 
+// webApp strips an app of the settings web clients must not see: the saved
+// password, only ever read back by this machine, and the environment, which
+// often holds API keys and tokens
+func webApp(app config.App) config.App {
+	app.RunAsPassword = ""
+	app.Env = nil
+	return app
+}
+
 func (s *Server) isAuthorized(r *http.Request) bool {
 	if !s.Config.GetSettings().AuthEnabled {
 		return true
@@ -289,24 +359,581 @@ func (s *Server) isAuthorized(r *http.Request) bool {
 	return true
 }
 
-func (s *Server) handleLaunch(w http.ResponseWriter, appID string) {
+func (s *Server) handleLaunch(w http.ResponseWriter, r *http.Request, appID string) {
 	app, found := s.Config.GetAppByID(appID)
 	if !found {
 		http.Error(w, `{"error": "App not found"}`, http.StatusNotFound)
 		return
 	}
 
-	pid, err := launcher.RunExecutableWithTracking(app.ID, app.Name, app.Path, app.Args)
+	// ?wait=ready answers only once the app passes its readiness probe
+	wait := r.URL.Query().Get("wait")
+	timeout, err := parseTimeout(r)
+	if err == nil && wait != "" && wait != "ready" {
+		err = fmt.Errorf("invalid wait: %q (expected ready)", wait)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Check the parameter values before starting anything
+	values, err := launchValues(r)
+	if err == nil {
+		_, err = app.ResolveArguments(values)
+	}
+	if err != nil {
+		s.auditRequest(r, audit.Entry{Action: audit.ActionLaunch, AppID: app.ID, AppName: app.Name, Error: err.Error()})
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Dependencies first, unless the launch is about to be refused anyway
+	var deps []StepResult
+	if len(app.DependsOn) > 0 && (app.AllowMultipleInstances || len(s.appPIDs(app.ID)) == 0) {
+		deps, err = s.startDependencies(r.Context(), app, func(e audit.Entry) { s.auditRequest(r, e) })
+		if err != nil {
+			s.auditRequest(r, audit.Entry{Action: audit.ActionLaunch, AppID: app.ID, AppName: app.Name, Error: err.Error()})
+			w.WriteHeader(http.StatusFailedDependency)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":        err.Error(),
+				"dependencies": deps,
+			})
+			return
+		}
+	}
+
+	pid, pids, err := s.startApp(app, values)
+	if errors.Is(err, ErrAlreadyRunning) {
+		s.refuseLaunch(w, r, app, pids)
+		return
+	}
+
+	entry := audit.Entry{Action: audit.ActionLaunch, AppID: app.ID, AppName: app.Name}
 	if err != nil {
+		entry.Error = err.Error()
+		s.auditRequest(r, entry)
 		log.Printf("Error launching %s: %v", app.Name, err)
+		w.WriteHeader(launchErrorStatus(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	entry.Details = map[string]interface{}{"pid": pid}
+
+	response := map[string]interface{}{
+		"status":  "success",
+		"message": "Launched " + app.Name,
+		"pid":     pid,
+	}
+	if deps != nil {
+		response["dependencies"] = deps
+	}
+
+	if wait == "ready" {
+		startedAt := time.Now()
+		pids, err := s.waitReady(r.Context(), app, pid, timeout)
+		if err != nil {
+			entry.Error = "not ready: " + err.Error()
+			s.auditRequest(r, entry)
+			failure := map[string]interface{}{
+				"error": fmt.Sprintf("%s was launched but is not ready: %v", app.Name, err),
+				"pid":   pid,
+			}
+			if deps != nil {
+				failure["dependencies"] = deps
+			}
+			w.WriteHeader(http.StatusGatewayTimeout)
+			json.NewEncoder(w).Encode(failure)
+			return
+		}
+		readyAfter := time.Since(startedAt).Seconds()
+		entry.Details["ready_after"] = readyAfter
+		response["message"] = app.Name + " is ready"
+		response["pids"] = pids
+		response["ready_after"] = readyAfter
+	}
+
+	s.auditRequest(r, entry)
+	json.NewEncoder(w).Encode(response)
+}
+
+// refuseLaunch answers a launch of a single-instance app that is already running,
+// bringing the running instance to front if the app asks for it
+func (s *Server) refuseLaunch(w http.ResponseWriter, r *http.Request, app config.App, pids []int) {
+	focused := app.FocusExisting && launcher.BringToFront(pids)
+	s.auditRequest(r, audit.Entry{Action: audit.ActionLaunch, AppID: app.ID, AppName: app.Name, Error: "already running",
+		Details: map[string]interface{}{"pids": pids, "focused": focused}})
+
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   app.Name + " is already running",
+		"pids":    pids,
+		"focused": focused,
+	})
+}
+
+// startApp launches an app unless it is single-instance and already running,
+// in which case it returns ErrAlreadyRunning with the running PIDs
+func (s *Server) startApp(app config.App, values map[string]string) (int, []int, error) {
+	s.launchMu.Lock()
+	defer s.launchMu.Unlock()
+
+	if !app.AllowMultipleInstances {
+		if pids := s.appPIDs(app.ID); len(pids) > 0 {
+			return 0, pids, ErrAlreadyRunning
+		}
+	}
+	pid, err := s.launchApp(app, values)
+	return pid, nil, err
+}
+
+// launchApp starts an app with the given parameter values (nil for the defaults)
+// and registers the new PID with the process monitor
+func (s *Server) launchApp(app config.App, values map[string]string) (int, error) {
+	args, err := app.ResolveArguments(values)
+	if err != nil {
+		return 0, err
+	}
+
+	elevation, err := app.LaunchElevation(s.Config.GetSettings())
+	if err != nil {
+		return 0, &launcher.ElevationError{Mode: app.Elevation, Reason: err.Error()}
+	}
+
+	pid, err := launcher.RunExecutableWithTracking(app.ID, app.Name, app.Path, args, launcher.Options{
+		Kind:          app.Kind,
+		Interpreter:   app.Interpreter,
+		WorkingDir:    app.WorkingDir,
+		Env:           app.Env,
+		CaptureOutput: app.CaptureOutput,
+		Elevation:     elevation,
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.ProcessMonitor.TrackLaunch(app.ID, pid)
+	s.rememberValues(app.ID, values)
+	s.events.publish(EventLaunch, map[string]interface{}{"app_id": app.ID, "name": app.Name, "pid": pid})
+	s.refreshStatusSoon()
+	return pid, nil
+}
+
+// launchErrorStatus is the HTTP status of a failed launch: 403 when the app could
+// not get the rights it asks for, 500 otherwise
+func launchErrorStatus(err error) int {
+	var elevationErr *launcher.ElevationError
+	if errors.As(err, &elevationErr) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// parseTimeout reads the optional ?timeout= override (seconds), zero if absent
+func parseTimeout(r *http.Request) (time.Duration, error) {
+	t := r.URL.Query().Get("timeout")
+	if t == "" {
+		return 0, nil
+	}
+	secs, err := strconv.Atoi(t)
+	if err != nil || secs < 0 {
+		return 0, fmt.Errorf("invalid timeout: %q", t)
+	}
+	return time.Duration(secs) * time.Second, nil
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request, appID string) {
+	timeout, err := parseTimeout(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	app, _ := s.Config.GetAppByID(appID)
+	result, err := s.StopApp(appID, timeout)
+	if !errors.Is(err, ErrAppNotFound) {
+		s.auditRequest(r, stopAuditEntry(app, result, err))
+	}
+	switch {
+	case errors.Is(err, ErrAppNotFound):
+		http.Error(w, `{"error": "App not found"}`, http.StatusNotFound)
+		return
+	case errors.Is(err, ErrNotRunning):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("Error stopping %s: %v", app.Name, err)
 		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":  err.Error(),
+			"pids":   result.PIDs,
+			"forced": result.Forced,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": "Stopped " + app.Name,
+		"pids":    result.PIDs,
+		"forced":  result.Forced,
+	})
+}
+
+func (s *Server) handleRestart(w http.ResponseWriter, r *http.Request, appID string) {
+	timeout, err := parseTimeout(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
 
+	app, found := s.Config.GetAppByID(appID)
+	if !found {
+		http.Error(w, `{"error": "App not found"}`, http.StatusNotFound)
+		return
+	}
+
+	// 1. Stop every matching process (skipped if nothing is running)
+	stopPhase := map[string]interface{}{"status": "success"}
+	result, err := s.StopApp(app.ID, timeout)
+	switch {
+	case errors.Is(err, ErrNotRunning):
+		stopPhase["status"] = "skipped"
+	case err != nil:
+		log.Printf("Error stopping %s for restart: %v", app.Name, err)
+		stopPhase["status"] = "error"
+		stopPhase["error"] = err.Error()
+		stopPhase["pids"] = result.PIDs
+		stopPhase["forced"] = result.Forced
+
+		// Relaunching next to a process that refused to die would leave two copies
+		s.auditRequest(r, audit.Entry{Action: audit.ActionRestart, AppID: app.ID, AppName: app.Name, Error: "stop failed: " + err.Error(),
+			Details: map[string]interface{}{"pids": result.PIDs, "forced": result.Forced}})
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Restart aborted: " + err.Error(),
+			"phases": map[string]interface{}{
+				"stop":   stopPhase,
+				"launch": map[string]interface{}{"status": "skipped"},
+			},
+		})
+		return
+	default:
+		stopPhase["pids"] = result.PIDs
+		stopPhase["forced"] = result.Forced
+	}
+
+	entry := audit.Entry{Action: audit.ActionRestart, AppID: app.ID, AppName: app.Name,
+		Details: map[string]interface{}{"stop": stopPhase["status"], "pids": result.PIDs, "forced": result.Forced}}
+	phases := map[string]interface{}{"stop": stopPhase}
+
+	// 2. Dependencies that stopped in the meantime, as for a launch
+	if len(app.DependsOn) > 0 {
+		deps, err := s.startDependencies(r.Context(), app, func(e audit.Entry) { s.auditRequest(r, e) })
+		phases["dependencies"] = deps
+		if err != nil {
+			entry.Error = "dependencies failed: " + err.Error()
+			s.auditRequest(r, entry)
+			phases["launch"] = map[string]interface{}{"status": "skipped"}
+			w.WriteHeader(http.StatusFailedDependency)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": err.Error(), "phases": phases})
+			return
+		}
+	}
+
+	// 3. Launch a fresh instance, with the parameters of the last launch.
+	// A concurrent launch may have started one since the stop.
+	pid, pids, err := s.startApp(app, s.lastLaunchValues(app.ID))
+	switch {
+	case errors.Is(err, ErrAlreadyRunning):
+		entry.Error = "already running"
+		entry.Details["running_pids"] = pids
+		s.auditRequest(r, entry)
+		phases["launch"] = map[string]interface{}{"status": "already_running", "pids": pids}
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":  app.Name + " was started again by someone else",
+			"pids":   pids,
+			"phases": phases,
+		})
+		return
+
+	case err != nil:
+		entry.Error = "launch failed: " + err.Error()
+		s.auditRequest(r, entry)
+		log.Printf("Error relaunching %s: %v", app.Name, err)
+		phases["launch"] = map[string]interface{}{"status": "error", "error": err.Error()}
+		w.WriteHeader(launchErrorStatus(err))
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": err.Error(), "phases": phases})
+		return
+	}
+
+	entry.Details["pid"] = pid
+	s.auditRequest(r, entry)
+	phases["launch"] = map[string]interface{}{"status": "success", "pid": pid}
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
-		"message": "Launched " + app.Name,
+		"message": "Restarted " + app.Name,
 		"pid":     pid,
+		"phases":  phases,
 	})
 }
+
+// StopApp closes every process of an app: the one started by the launcher and
+// any others the process monitor attributes to it. A zero timeout uses the
+// configured stop timeout.
+func (s *Server) StopApp(appID string, timeout time.Duration) (launcher.StopResult, error) {
+	app, found := s.Config.GetAppByID(appID)
+	if !found {
+		return launcher.StopResult{}, ErrAppNotFound
+	}
+	s.supervisor.cancel(app.ID) // A stop also ends a pending automatic restart
+
+	if timeout == 0 {
+		timeout = launcher.DefaultStopTimeout
+		if secs := s.Config.GetSettings().StopTimeout; secs > 0 {
+			timeout = time.Duration(secs) * time.Second
+		}
+	}
+
+	pids := s.appPIDs(app.ID)
+	if len(pids) == 0 {
+		return launcher.StopResult{}, ErrNotRunning
+	}
+
+	log.Printf("Stopping %s (PIDs %v)", app.Name, pids)
+	result, err := launcher.StopProcesses(pids, timeout)
+	if err == nil {
+		s.events.publish(EventStop, map[string]interface{}{"app_id": app.ID, "pids": result.PIDs, "forced": result.Forced})
+	}
+
+	// Refresh statuses now rather than on the next poll
+	if err := s.ProcessMonitor.Update(); err != nil {
+		log.Printf("Error updating process monitor: %v", err)
+	}
+	return result, err
+}
+
+// stopAuditEntry describes the outcome of StopApp for the audit log
+func stopAuditEntry(app config.App, result launcher.StopResult, err error) audit.Entry {
+	entry := audit.Entry{Action: audit.ActionStop, AppID: app.ID, AppName: app.Name}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if len(result.PIDs) > 0 {
+		entry.Details = map[string]interface{}{"pids": result.PIDs, "forced": result.Forced}
+	}
+	return entry
+}
+
+// appPIDs returns the processes of an app: those the monitor matched in its
+// last scan plus the one started by the launcher, which may be too recent for the scan
+func (s *Server) appPIDs(appID string) []int {
+	pids := s.ProcessMonitor.GetPIDs(appID)
+	if running, pid := launcher.GetProcessStatus(appID); running && !containsPID(pids, pid) {
+		pids = append(pids, pid)
+	}
+	return pids
+}
+
+func containsPID(pids []int, pid int) bool {
+	for _, p := range pids {
+		if p == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAppAPI routes the per-app endpoints under /api/apps/{id}/
+func (s *Server) handleAppAPI(w http.ResponseWriter, r *http.Request) {
+	appID, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/apps/"), "/")
+
+	app, found := s.Config.GetAppByID(appID)
+	if !found {
+		http.Error(w, `{"error": "App not found"}`, http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "status" && r.Method == "GET":
+		s.handleAppStatus(w, app)
+
+	case action == "parameters" && r.Method == "GET":
+		s.handleParameters(w, app)
+
+	case action == "logs" && r.Method == "GET":
+		s.handleLogs(w, r, app)
+
+	case action == "logs/stream" && r.Method == "GET":
+		s.handleLogStream(w, r, app)
+
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
+
+// appStatus is the detailed status of an app reported by the status API
+type appStatus struct {
+	processmon.AppStatus
+	Health      string             `json:"health"`                 // running, unhealthy or stopped
+	HealthCheck *HealthStatus      `json:"health_check,omitempty"` // Set when the app has a health check
+	LastExit    *launcher.ExitInfo `json:"last_exit,omitempty"`    // Of the last launch by Aviator
+	Restarts    *RestartStatus     `json:"restarts,omitempty"`     // Set once the supervisor handled an exit
+}
+
+func (s *Server) appStatus(appID string, status processmon.AppStatus) appStatus {
+	if status.PIDs == nil {
+		status.PIDs, status.Processes = []int{}, []processmon.ProcessStats{} // Not watched yet
+	}
+	result := appStatus{AppStatus: status, Health: s.healthState(appID, status.Running)}
+	result.HealthCheck = s.health.status(appID)
+	if exit, ok := launcher.GetLastExit(appID); ok {
+		result.LastExit = &exit
+	}
+	result.Restarts = s.supervisor.status(appID)
+	return result
+}
+
+// handleAppStatus returns the PIDs and resource usage of an app's processes
+// from the last scan, and how its last launch ended
+func (s *Server) handleAppStatus(w http.ResponseWriter, app config.App) {
+	status, _ := s.ProcessMonitor.GetAppStatus(app.ID)
+
+	json.NewEncoder(w).Encode(struct {
+		AppID string `json:"app_id"`
+		Name  string `json:"name"`
+		appStatus
+	}{app.ID, app.Name, s.appStatus(app.ID, status)})
+}
+
+// handleExit records the end of a launched process and tells the connected clients
+func (s *Server) handleExit(exit launcher.ExitInfo) {
+	app, _ := s.Config.GetAppByID(exit.AppID)
+	entry := audit.Entry{
+		Action:  audit.ActionExit,
+		Source:  audit.SourceSystem,
+		AppID:   exit.AppID,
+		AppName: app.Name,
+		Details: map[string]interface{}{
+			"pid":              exit.PID,
+			"exit_code":        exit.ExitCode,
+			"duration_seconds": exit.Duration,
+			"stopped":          exit.Stopped,
+			"abnormal":         exit.Abnormal,
+		},
+	}
+	if exit.Abnormal {
+		entry.Error = exit.Reason
+	}
+	s.RecordAudit(entry)
+	s.events.publish(EventExit, exit)
+	s.superviseExit(exit)
+}
+
+// handleLogs returns the last ?tail=N lines (default 100) of an app's captured output
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request, app config.App) {
+	tail := 100
+	if t := r.URL.Query().Get("tail"); t != "" {
+		n, err := strconv.Atoi(t)
+		if err != nil || n <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid tail"})
+			return
+		}
+		tail = min(n, 5000)
+	}
+
+	lines, err := launcher.TailLog(app.ID, tail)
+	if err != nil {
+		log.Printf("Error reading logs of %s: %v", app.Name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"app_id":         app.ID,
+		"capture_output": app.CaptureOutput,
+		"lines":          lines,
+	})
+}
+
+// handleLogStream streams the live output of an app's running launch as Server-Sent Events.
+// Lines the client is too slow to receive are dropped (and counted) rather than
+// slowing down the process.
+func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request, app config.App) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, `{"error": "Streaming unsupported"}`, http.StatusInternalServerError)
+		return
+	}
+
+	sub, err := launcher.SubscribeOutput(app.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.streamCtx.Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprintf(w, ": ping\n\n")
+			flusher.Flush()
+		case ev, open := <-sub.Events():
+			if !open {
+				fmt.Fprintf(w, "event: exit\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+			writeOutputEvent(w, ev)
+
+			// Batch whatever is already queued into a single flush
+			for pending := len(sub.Events()); pending > 0; pending-- {
+				ev, open := <-sub.Events()
+				if !open {
+					break
+				}
+				writeOutputEvent(w, ev)
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeOutputEvent(w http.ResponseWriter, ev launcher.OutputEvent) {
+	if ev.Dropped > 0 {
+		fmt.Fprintf(w, "event: dropped\ndata: %d\n\n", ev.Dropped)
+		return
+	}
+	writeSSEData(w, "output", ev.Line)
+}
+
+// writeSSEData writes one event, splitting multi-line payloads into data fields
+func writeSSEData(w http.ResponseWriter, event, data string) {
+	fmt.Fprintf(w, "event: %s\n", event)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
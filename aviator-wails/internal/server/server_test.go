@@ -0,0 +1,90 @@
+package server
+
+import (
+	"aviator-wails/internal/audit"
+	"aviator-wails/internal/config"
+	"aviator-wails/internal/launcher"
+	"aviator-wails/internal/processmon"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// noProcesses is an empty process table
+type noProcesses struct{}
+
+func (noProcesses) Processes() ([]processmon.Process, error) { return nil, nil }
+
+func TestAppsHideSecrets(t *testing.T) {
+	cm := &config.ConfigManager{Apps: []config.App{{
+		ID:            "app",
+		Name:          "App",
+		Path:          "/usr/bin/app",
+		Env:           map[string]string{"API_KEY": "s3cret"},
+		RunAsUser:     "svc",
+		RunAsPassword: "protected-password",
+	}}}
+	s := &Server{Config: cm}
+
+	rec := httptest.NewRecorder()
+	s.handleAPI(rec, httptest.NewRequest("GET", "/api/apps", nil))
+
+	body := rec.Body.String()
+	for _, secret := range []string{"s3cret", "API_KEY", "protected-password"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("response shows %q: %s", secret, body)
+		}
+	}
+	var apps []config.App
+	if err := json.Unmarshal(rec.Body.Bytes(), &apps); err != nil || len(apps) != 1 || apps[0].RunAsUser != "svc" {
+		t.Errorf("got %s, %v", body, err)
+	}
+
+	// The config itself keeps them
+	if app, _ := cm.GetAppByID("app"); app.Env["API_KEY"] != "s3cret" || app.RunAsPassword == "" {
+		t.Errorf("config changed: %+v", app)
+	}
+}
+
+func TestRestartStartsDependencies(t *testing.T) {
+	dir := t.TempDir()
+	cm := &config.ConfigManager{Apps: []config.App{
+		{ID: "web", Name: "Web", Path: filepath.Join(dir, "web"), DependsOn: []string{"db"}},
+		{ID: "db", Name: "DB", Path: filepath.Join(dir, "missing-db")},
+	}}
+	s := &Server{
+		Config:         cm,
+		ProcessMonitor: processmon.NewProcessMonitorWithEnumerator(noProcesses{}),
+		Audit:          audit.NewLog(filepath.Join(dir, "audit.jsonl")),
+		supervisor:     newSupervisor(),
+		lastValues:     make(map[string]map[string]string),
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleAPI(rec, httptest.NewRequest("POST", "/api/restart/web", nil))
+
+	if rec.Code != http.StatusFailedDependency {
+		t.Fatalf("got %d, want %d: %s", rec.Code, http.StatusFailedDependency, rec.Body)
+	}
+	var body struct {
+		Phases struct {
+			Dependencies []StepResult           `json:"dependencies"`
+			Launch       map[string]interface{} `json:"launch"`
+		} `json:"phases"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if deps := body.Phases.Dependencies; len(deps) != 1 || deps[0].AppID != "db" || deps[0].Status != StepError {
+		t.Errorf("dependencies: %+v", deps)
+	}
+	if body.Phases.Launch["status"] != "skipped" {
+		t.Errorf("launch: %v", body.Phases.Launch)
+	}
+	if running, _ := launcher.GetProcessStatus("web"); running {
+		t.Error("web was launched without its dependency")
+	}
+}
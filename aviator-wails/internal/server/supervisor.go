@@ -0,0 +1,204 @@
+package server
+
+import (
+	"aviator-wails/internal/audit"
+	"aviator-wails/internal/config"
+	"aviator-wails/internal/launcher"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBackoff    = time.Second
+	defaultMaxBackoff = 5 * time.Minute
+
+	// stableRunTime is how long a launch must last for the retry count to start over
+	stableRunTime = time.Minute
+)
+
+// RestartStatus reports what the supervisor did for an app
+type RestartStatus struct {
+	Count         int        `json:"count"`                     // Consecutive restarts since the app last ran stably
+	Total         int        `json:"total"`                     // Restarts since Aviator started
+	NextRestartAt *time.Time `json:"next_restart_at,omitempty"` // Pending restart, if any
+	GaveUp        bool       `json:"gave_up"`                   // Max retries reached
+}
+
+// restartState is the supervisor bookkeeping of one app
+type restartState struct {
+	RestartStatus
+	timer      *time.Timer
+	generation int // Bumped by cancel, so a restart under way does not schedule another
+}
+
+// supervisor relaunches apps according to their restart policy
+type supervisor struct {
+	states map[string]*restartState
+	mu     sync.Mutex
+}
+
+func newSupervisor() *supervisor {
+	return &supervisor{states: make(map[string]*restartState)}
+}
+
+func (sv *supervisor) state(appID string) *restartState {
+	st, ok := sv.states[appID]
+	if !ok {
+		st = &restartState{}
+		sv.states[appID] = st
+	}
+	return st
+}
+
+// status returns the restart counters of an app, nil if it was never restarted
+func (sv *supervisor) status(appID string) *RestartStatus {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	st, ok := sv.states[appID]
+	if !ok {
+		return nil
+	}
+	status := st.RestartStatus
+	return &status
+}
+
+// cancel drops a pending restart, e.g. because the app was stopped on purpose
+func (sv *supervisor) cancel(appID string) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	st, ok := sv.states[appID]
+	if !ok {
+		return
+	}
+	st.generation++
+	if st.timer != nil {
+		st.timer.Stop()
+		st.timer = nil
+		st.NextRestartAt = nil
+	}
+}
+
+// shouldRestart applies the restart policy of an app to the way a launch ended.
+// Documents and URLs are never restarted, their opener exits as soon as it is done.
+func shouldRestart(app config.App, exit launcher.ExitInfo) bool {
+	policy := app.Restart
+	if policy == nil || exit.Stopped || app.OpenedByHandler() {
+		return false
+	}
+	switch policy.Mode {
+	case config.RestartAlways:
+		return true
+	case config.RestartOnFailure:
+		return exit.Abnormal
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the given restart attempt (from 0)
+func backoff(policy *config.RestartPolicy, attempt int) time.Duration {
+	delay, limit := defaultBackoff, defaultMaxBackoff
+	if policy.BackoffSeconds > 0 {
+		delay = time.Duration(policy.BackoffSeconds) * time.Second
+	}
+	if policy.MaxBackoffSeconds > 0 {
+		limit = time.Duration(policy.MaxBackoffSeconds) * time.Second
+	}
+	for i := 0; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// superviseExit schedules a restart of the app if its policy asks for one
+func (s *Server) superviseExit(exit launcher.ExitInfo) {
+	app, found := s.Config.GetAppByID(exit.AppID)
+	if !found {
+		return
+	}
+
+	sv := s.supervisor
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	st := sv.state(app.ID)
+	if exit.Duration >= stableRunTime.Seconds() {
+		st.Count = 0 // It ran fine for a while, this is a new failure streak
+		st.GaveUp = false
+	}
+	if !shouldRestart(app, exit) {
+		return
+	}
+	s.scheduleRestart(app, st)
+}
+
+// scheduleRestart arms the restart timer, the caller holds the supervisor lock
+func (s *Server) scheduleRestart(app config.App, st *restartState) {
+	policy := app.Restart
+	if policy.MaxRetries > 0 && st.Count >= policy.MaxRetries {
+		if !st.GaveUp {
+			st.GaveUp = true
+			log.Printf("Not restarting %s: gave up after %d attempts", app.Name, st.Count)
+			s.RecordAudit(audit.Entry{Action: audit.ActionRestart, Source: audit.SourceSystem, AppID: app.ID, AppName: app.Name,
+				Error: "max retries reached", Details: map[string]interface{}{"attempts": st.Count}})
+		}
+		return
+	}
+
+	delay := backoff(policy, st.Count)
+	next := time.Now().Add(delay)
+	st.NextRestartAt = &next
+	if st.timer != nil {
+		st.timer.Stop()
+	}
+	st.timer = time.AfterFunc(delay, func() { s.restartApp(app.ID) })
+	log.Printf("Restarting %s in %s (attempt %d)", app.Name, delay, st.Count+1)
+}
+
+// restartApp relaunches a supervised app when its restart timer fires. The launch
+// goes through startApp like any other and runs without the supervisor lock, so
+// a slow one does not hold up the status of every app.
+func (s *Server) restartApp(appID string) {
+	sv := s.supervisor
+	sv.mu.Lock()
+	st := sv.state(appID)
+	st.timer = nil
+	st.NextRestartAt = nil
+
+	// The app may have been removed, changed or started by hand in the meantime
+	app, found := s.Config.GetAppByID(appID)
+	if !found || !shouldRestart(app, launcher.ExitInfo{Abnormal: true}) || len(s.appPIDs(appID)) > 0 {
+		sv.mu.Unlock()
+		return
+	}
+	st.Count++
+	st.Total++
+	attempt, generation := st.Count, st.generation
+	sv.mu.Unlock()
+
+	pid, _, err := s.startApp(app, s.lastLaunchValues(app.ID))
+
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if errors.Is(err, ErrAlreadyRunning) {
+		st.Count-- // Launched by someone else first, this was no attempt
+		st.Total--
+		return
+	}
+	entry := audit.Entry{Action: audit.ActionRestart, Source: audit.SourceSystem, AppID: app.ID, AppName: app.Name,
+		Details: map[string]interface{}{"attempt": attempt}}
+	if err != nil {
+		entry.Error = err.Error()
+		log.Printf("Error restarting %s: %v", app.Name, err)
+		if st.generation == generation {
+			s.scheduleRestart(app, st) // A failed launch counts as a failed attempt
+		}
+	} else {
+		entry.Details["pid"] = pid
+	}
+	s.RecordAudit(entry)
+}
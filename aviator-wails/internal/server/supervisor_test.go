@@ -0,0 +1,103 @@
+package server
+
+import (
+	"aviator-wails/internal/audit"
+	"aviator-wails/internal/config"
+	"aviator-wails/internal/launcher"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestShouldRestart(t *testing.T) {
+	crashed := launcher.ExitInfo{ExitCode: 1, Abnormal: true}
+	clean := launcher.ExitInfo{ExitCode: 0}
+	stopped := launcher.ExitInfo{ExitCode: 1, Stopped: true}
+
+	policy := func(mode string) *config.RestartPolicy { return &config.RestartPolicy{Mode: mode} }
+	tests := []struct {
+		name string
+		app  config.App
+		exit launcher.ExitInfo
+		want bool
+	}{
+		{"no policy", config.App{}, crashed, false},
+		{"never", config.App{Restart: policy(config.RestartNever)}, crashed, false},
+		{"on-failure after a crash", config.App{Restart: policy(config.RestartOnFailure)}, crashed, true},
+		{"on-failure after a clean exit", config.App{Restart: policy(config.RestartOnFailure)}, clean, false},
+		{"always after a crash", config.App{Restart: policy(config.RestartAlways)}, crashed, true},
+		{"always after a clean exit", config.App{Restart: policy(config.RestartAlways)}, clean, true},
+		{"always after a stop", config.App{Restart: policy(config.RestartAlways)}, stopped, false},
+		{"on-failure after a stop", config.App{Restart: policy(config.RestartOnFailure)}, stopped, false},
+		{"document", config.App{Kind: launcher.KindDocument, Restart: policy(config.RestartAlways)}, clean, false},
+		{"url", config.App{Kind: launcher.KindURL, Restart: policy(config.RestartAlways)}, crashed, false},
+		{"script", config.App{Kind: launcher.KindScript, Restart: policy(config.RestartAlways)}, clean, true},
+	}
+	for _, tt := range tests {
+		if got := shouldRestart(tt.app, tt.exit); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		policy  config.RestartPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{config.RestartPolicy{}, 0, time.Second},
+		{config.RestartPolicy{}, 1, 2 * time.Second},
+		{config.RestartPolicy{}, 5, 32 * time.Second},
+		{config.RestartPolicy{}, 8, 256 * time.Second},
+		{config.RestartPolicy{}, 9, defaultMaxBackoff},    // 512s is over the default cap
+		{config.RestartPolicy{}, 1000, defaultMaxBackoff}, // No overflow
+		{config.RestartPolicy{BackoffSeconds: 3}, 2, 12 * time.Second},
+		{config.RestartPolicy{BackoffSeconds: 3, MaxBackoffSeconds: 10}, 1, 6 * time.Second},
+		{config.RestartPolicy{BackoffSeconds: 3, MaxBackoffSeconds: 10}, 2, 10 * time.Second},
+		{config.RestartPolicy{BackoffSeconds: 30, MaxBackoffSeconds: 10}, 0, 10 * time.Second}, // First delay over the cap
+	}
+	for _, tt := range tests {
+		if got := backoff(&tt.policy, tt.attempt); got != tt.want {
+			t.Errorf("backoff(%+v, %d) = %s, want %s", tt.policy, tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleRestartRetryLimit(t *testing.T) {
+	s := &Server{supervisor: newSupervisor(), Audit: audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))}
+	app := config.App{ID: "app", Name: "App", Restart: &config.RestartPolicy{Mode: config.RestartAlways, MaxRetries: 2, BackoffSeconds: 3600}}
+
+	tests := []struct {
+		count      int
+		wantTimer  bool
+		wantGaveUp bool
+	}{
+		{0, true, false},
+		{1, true, false},
+		{2, false, true},
+		{5, false, true},
+	}
+	for _, tt := range tests {
+		st := &restartState{RestartStatus: RestartStatus{Count: tt.count}}
+		s.scheduleRestart(app, st)
+		if (st.timer != nil) != tt.wantTimer || (st.NextRestartAt != nil) != tt.wantTimer {
+			t.Errorf("after %d restarts: scheduled %v, want %v", tt.count, st.timer != nil, tt.wantTimer)
+		}
+		if st.GaveUp != tt.wantGaveUp {
+			t.Errorf("after %d restarts: gave up %v, want %v", tt.count, st.GaveUp, tt.wantGaveUp)
+		}
+		if st.timer != nil {
+			st.timer.Stop()
+		}
+	}
+
+	// Unlimited retries never give up
+	app.Restart.MaxRetries = 0
+	st := &restartState{RestartStatus: RestartStatus{Count: 100}}
+	s.scheduleRestart(app, st)
+	if st.timer == nil || st.GaveUp {
+		t.Errorf("unlimited retries: scheduled %v, gave up %v", st.timer != nil, st.GaveUp)
+	}
+	st.timer.Stop()
+}
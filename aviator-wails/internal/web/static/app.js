@@ -42,9 +42,11 @@ const toastMsg = document.getElementById('toast-message');
 
 // Track process statuses
 let processStatuses = {};
+let healthStatuses = {}; // Apps with a health check, see appHealth
+let appNames = {}; // appID -> name, for notifications
 let serverOnline = true;
-let processStatusInterval = null;
-let serverStatusInterval = null;
+let eventSource = null;
+let reconnectTimer = null;
 
 let currentHostname = '...';
 
@@ -116,7 +118,7 @@ async function handleLogin() {
             hideAuthModal();
             await fetchInfo(); // Update header controls
             await fetchApps();
-            startPolling();
+            startLiveUpdates();
             showToast('✅ Authorization successful!', 2000);
         } else {
             err.classList.remove('opacity-0');
@@ -189,10 +191,10 @@ function updateServerStatus(online) {
         if (offlineBanner) offlineBanner.classList.add('hidden');
         document.getElementById('view-section').classList.remove('hidden');
 
-        // If was offline, restart polling and reload apps
+        // If was offline, reconnect live updates and reload apps
         if (wasOffline) {
             showToast('✅ Server reconnected!', 3000);
-            startPolling();
+            startLiveUpdates();
             fetchApps();
         }
     } else {
@@ -209,8 +211,8 @@ function updateServerStatus(online) {
         // Clear apps grid when offline (completely remove content)
         grid.innerHTML = '';
 
-        // Stop polling to save resources
-        stopPolling();
+        // Stop live updates to save resources
+        stopLiveUpdates();
 
         // Show toast notification only on first disconnect
         if (wasOffline === false) {
@@ -219,25 +221,74 @@ function updateServerStatus(online) {
     }
 }
 
-function startPolling() {
-    // Clear any existing intervals
-    stopPolling();
+// Live updates: the server pushes status changes over /api/events instead of being polled
+function startLiveUpdates() {
+    stopLiveUpdates();
 
-    // Start process status polling every 2 seconds
-    processStatusInterval = setInterval(fetchProcessStatuses, 2000);
+    eventSource = new EventSource(`${API_BASE}/api/events`);
 
-    // Start server status polling every 5 seconds
-    serverStatusInterval = setInterval(fetchInfo, 5000);
+    // Full state on every (re)connection
+    eventSource.addEventListener('snapshot', (e) => {
+        const snapshot = JSON.parse(e.data).data;
+        processStatuses = snapshot.statuses || {};
+        healthStatuses = snapshot.health || {};
+        updateStatusIndicators();
+    });
+
+    eventSource.addEventListener('status', (e) => {
+        const change = JSON.parse(e.data).data;
+        processStatuses[change.app_id] = change.running;
+        updateStatusIndicators();
+    });
+
+    eventSource.addEventListener('health', (e) => {
+        const change = JSON.parse(e.data).data;
+        healthStatuses[change.app_id] = change;
+        updateStatusIndicators();
+    });
+
+    eventSource.addEventListener('exit', (e) => {
+        const exit = JSON.parse(e.data).data;
+        if (exit.abnormal) {
+            const name = appNames[exit.app_id] || 'An app';
+            showToast(`⚠️ ${name} crashed after ${formatUptime(exit.duration_seconds)} (${exit.reason || 'exit code ' + exit.exit_code})`, 6000);
+        }
+        if (currentlySelectedApp && currentlySelectedApp.id === exit.app_id) updateModalStatus();
+    });
+
+    eventSource.addEventListener('config', (e) => {
+        const change = JSON.parse(e.data).data;
+        if (change.kind === 'apps') {
+            fetchApps();
+        } else if (change.kind === 'groups') {
+            fetchGroups();
+        } else if (change.kind === 'settings') {
+            fetchInfo(); // PIN may have been enabled or changed
+        }
+    });
+
+    eventSource.addEventListener('server', () => {
+        stopLiveUpdates();
+        updateServerStatus(false);
+    });
+
+    eventSource.onerror = async () => {
+        // EventSource retries network errors by itself, check whether the host is still there
+        const closed = eventSource && eventSource.readyState === EventSource.CLOSED;
+        const ok = await fetchInfo();
+        if (ok && closed && serverOnline) {
+            clearTimeout(reconnectTimer);
+            reconnectTimer = setTimeout(startLiveUpdates, 3000);
+        }
+    };
 }
 
-function stopPolling() {
-    if (processStatusInterval) {
-        clearInterval(processStatusInterval);
-        processStatusInterval = null;
-    }
-    if (serverStatusInterval) {
-        clearInterval(serverStatusInterval);
-        serverStatusInterval = null;
+function stopLiveUpdates() {
+    clearTimeout(reconnectTimer);
+    reconnectTimer = null;
+    if (eventSource) {
+        eventSource.close();
+        eventSource = null;
     }
 }
 
@@ -268,8 +319,7 @@ async function retryConnection() {
             // Reload everything
             console.log('Retry successful, reloading apps...');
             await fetchApps();
-            await fetchProcessStatuses();
-            startPolling();
+            startLiveUpdates();
 
             showToast('✅ Connected successfully!', 3000);
 
@@ -292,23 +342,17 @@ async function retryConnection() {
 }
 
 
-async function fetchProcessStatuses() {
-    if (!serverOnline) return;
+// appHealth is running, unhealthy (running but failing its health check) or stopped
+function appHealth(appId) {
+    if (!processStatuses[appId]) return 'stopped';
+    const health = healthStatuses[appId];
+    return health && health.state === 'unhealthy' ? 'unhealthy' : 'running';
+}
 
-    try {
-        const response = await fetch(`${API_BASE}/api/process-statuses`);
-        if (!response.ok) {
-            if (response.status !== 401) updateServerStatus(false);
-            return;
-        }
-        const data = await response.json();
-        processStatuses = data;
-        updateStatusIndicators();
-        updateServerStatus(true);
-    } catch (e) {
-        console.error('Failed to fetch process statuses:', e);
-        if (e.message !== 'Unauthorized') updateServerStatus(false);
-    }
+function ledTitle(appId) {
+    const health = appHealth(appId);
+    if (health === 'unhealthy') return `Unhealthy: ${healthStatuses[appId].error || 'health check failing'}`;
+    return health === 'running' ? 'Running' : 'Stopped';
 }
 
 function updateStatusIndicators() {
@@ -317,11 +361,9 @@ function updateStatusIndicators() {
         const appId = card.getAttribute('data-app-id');
         const led = card.querySelector('.status-led');
         if (led) {
-            if (processStatuses[appId]) {
-                led.classList.add('led-running');
-            } else {
-                led.classList.remove('led-running');
-            }
+            led.classList.toggle('led-running', !!processStatuses[appId]);
+            led.classList.toggle('led-unhealthy', appHealth(appId) === 'unhealthy');
+            led.title = ledTitle(appId);
         }
     });
     updateModalStatus();
@@ -333,6 +375,7 @@ async function fetchApps() {
         if (!response.ok) return;
         const apps = await response.json();
         renderGrid(apps);
+        fetchGroups(); // Group tooltips list app names
     } catch (e) {
         console.error("Failed to fetch apps", e);
         if (e.message !== 'Unauthorized') {
@@ -343,6 +386,7 @@ async function fetchApps() {
 
 function renderGrid(apps) {
     grid.innerHTML = '';
+    appNames = Object.fromEntries(apps.map(app => [app.id, app.name]));
 
     if (apps.length === 0) {
         grid.innerHTML = `
@@ -378,7 +422,7 @@ function renderGrid(apps) {
             ${iconHTML}
             <div class="app-info flex flex-col items-center gap-1">
                 <div class="flex items-center gap-3">
-                    <div class="status-led ${processStatuses[app.id] ? 'led-running' : ''}" title="${processStatuses[app.id] ? 'Running' : 'Stopped'}"></div>
+                    <div class="status-led ${processStatuses[app.id] ? 'led-running' : ''} ${appHealth(app.id) === 'unhealthy' ? 'led-unhealthy' : ''}" title="${ledTitle(app.id)}"></div>
                     <h3 class="text-base font-semibold text-slate-100 group-hover:text-cyan-400 transition-colors truncate max-w-[120px]">${app.name}</h3>
                 </div>
                 <span class="launch-text text-[10px] text-slate-500 font-medium tracking-wider uppercase opacity-40 group-hover:opacity-100 transition-opacity">Launch App</span>
@@ -388,15 +432,111 @@ function renderGrid(apps) {
     });
 }
 
-async function launchApp(id, name) {
-    showToast(`Launching ${name}...`);
+async function fetchGroups() {
+    try {
+        const response = await fetch(`${API_BASE}/api/groups`);
+        if (!response.ok) return;
+        renderGroups(await response.json());
+    } catch (e) {
+        console.error("Failed to fetch groups", e);
+    }
+}
+
+function renderGroups(groups) {
+    const bar = document.getElementById('group-bar');
+    bar.innerHTML = '';
+    bar.classList.toggle('hidden', groups.length === 0);
+
+    groups.forEach(group => {
+        const btn = document.createElement('button');
+        btn.className = "glass-button py-2 px-4 rounded-full text-sm font-semibold flex items-center gap-2";
+        btn.title = group.steps.map(step => appNames[step.app_id] || '?').join(' → ');
+        btn.innerHTML = `
+            <svg width="14" height="14" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2.5"
+                stroke-linecap="round" stroke-linejoin="round"><polygon points="5 3 19 12 5 21 5 3"></polygon></svg>
+        `;
+        btn.appendChild(document.createTextNode(group.name));
+        btn.onclick = (e) => {
+            e.currentTarget.blur();
+            launchGroup(group.id, group.name);
+        };
+        bar.appendChild(btn);
+    });
+}
+
+async function launchGroup(id, name) {
+    showToast(`Launching ${name}...`, 3000);
+    try {
+        const response = await fetch(`${API_BASE}/api/groups/${id}/launch`, { method: 'POST' });
+        if (response.status === 401) return;
+        const data = await response.json();
+        if (response.ok) {
+            showToast(`${name}: ${data.steps.length} apps started`, 3000);
+        } else {
+            showToast(`Error: ${data.error || 'Internal error'}`, 5000);
+        }
+    } catch (e) {
+        if (e.message !== 'Unauthorized') showToast(`Network Error`, 3000);
+    }
+}
+
+async function launchApp(id, name, values, waitReady) {
+    showToast(waitReady ? `Launching ${name}, waiting until it is ready...` : `Launching ${name}...`);
+    try {
+        const options = { method: 'POST' };
+        if (values) {
+            options.headers = { 'Content-Type': 'application/json' };
+            options.body = JSON.stringify(values);
+        }
+        const wait = waitReady ? '?wait=ready' : '';
+        const response = await fetch(`${API_BASE}/api/launch/${id}${wait}`, options);
+        if (response.ok) {
+            const data = await response.json();
+            const started = (data.dependencies || []).filter(dep => dep.status === 'launched');
+            const deps = started.length > 0 ? ` (after ${started.map(dep => dep.app_name).join(', ')})` : '';
+            showToast(waitReady ? `${name} is ready!${deps}` : `${name} launched successfully!${deps}`, 3000);
+        } else if (response.status === 409) {
+            const err = await response.json();
+            showToast(err.focused ? `${name} is already running, brought to front` : `${name} is already running`, 3000);
+        } else if (response.status !== 401) {
+            const err = await response.json();
+            showToast(`Error: ${err.error || 'Internal error'}`, 4000);
+        }
+    } catch (e) {
+        if (e.message !== 'Unauthorized') showToast(`Network Error`, 3000);
+    }
+}
+
+async function stopApp(id, name) {
+    if (!confirm(`Stop ${name}? Unsaved work in the application may be lost.`)) return;
+
+    showToast(`Stopping ${name}...`);
+    try {
+        const response = await fetch(`${API_BASE}/api/stop/${id}`, { method: 'POST' });
+        if (response.ok) {
+            const data = await response.json();
+            const forced = data.forced && data.forced.length > 0 ? ' (forced)' : '';
+            showToast(`${name} stopped${forced}`, 3000);
+        } else if (response.status !== 401) {
+            const err = await response.json();
+            showToast(`Error: ${err.error || 'Internal error'}`, 4000);
+        }
+    } catch (e) {
+        if (e.message !== 'Unauthorized') showToast(`Network Error`, 3000);
+    }
+}
+
+async function restartApp(id, name) {
+    if (!confirm(`Restart ${name}? Unsaved work in the application may be lost.`)) return;
+
+    showToast(`Restarting ${name}...`);
     try {
-        const response = await fetch(`${API_BASE}/api/launch/${id}`, { method: 'POST' });
+        const response = await fetch(`${API_BASE}/api/restart/${id}`, { method: 'POST' });
         if (response.ok) {
-            showToast(`${name} launched successfully!`, 3000);
+            showToast(`${name} restarted`, 3000);
         } else if (response.status !== 401) {
             const err = await response.json();
-            showToast(`Error: ${err.detail || 'Internal error'}`, 4000);
+            showToast(`Error: ${err.error || 'Internal error'}`, 4000);
         }
     } catch (e) {
         if (e.message !== 'Unauthorized') showToast(`Network Error`, 3000);
@@ -411,6 +551,8 @@ function openAppDetails(app) {
     const iconContainer = document.getElementById('modal-app-icon');
     const nameContainer = document.getElementById('modal-app-name');
     const launchBtn = document.getElementById('modal-launch-btn');
+    const stopBtn = document.getElementById('modal-stop-btn');
+    const restartBtn = document.getElementById('modal-restart-btn');
 
     // Populate Data
     nameContainer.innerText = app.name;
@@ -424,30 +566,180 @@ function openAppDetails(app) {
     }
 
     // Set Launch Handlers
+    renderParameterForm(app);
     launchBtn.onclick = () => {
-        launchApp(app.id, app.name);
+        launchApp(app.id, app.name, collectParameterValues(), !!app.ready);
         // Optional: closeAppDetails();
     };
+    stopBtn.onclick = () => {
+        stopApp(app.id, app.name);
+    };
+    restartBtn.onclick = () => {
+        restartApp(app.id, app.name);
+    };
 
     updateModalStatus();
     modal.classList.remove('hidden');
 }
 
+// Launch parameters: a form field per parameter declared by the app
+async function renderParameterForm(app) {
+    const form = document.getElementById('modal-params');
+    form.innerHTML = '';
+    form.classList.add('hidden');
+    if (!app.parameters || app.parameters.length === 0) return;
+
+    let params;
+    try {
+        const response = await fetch(`${API_BASE}/api/apps/${app.id}/parameters`);
+        if (!response.ok) return;
+        params = await response.json();
+    } catch (e) {
+        console.error("Failed to fetch parameters", e);
+        return;
+    }
+    if (!currentlySelectedApp || currentlySelectedApp.id !== app.id) return; // Modal switched meanwhile
+
+    const inputClass = "w-full bg-slate-800/50 border border-white/10 rounded-xl px-3 py-2 text-sm focus:outline-none focus:border-cyan-500/50";
+    params.forEach(param => {
+        const label = document.createElement('label');
+        label.className = "block text-xs text-slate-400";
+        label.textContent = (param.label || param.name) + (param.required ? ' *' : '');
+
+        let field;
+        if (param.type === 'enum' || param.type === 'file') {
+            field = document.createElement('select');
+            const choices = param.type === 'enum' ? param.options : (param.files || []);
+            if (!param.required || !param.default) field.add(new Option('—', ''));
+            choices.forEach(choice => {
+                const text = param.type === 'file' ? choice.split(/[\\/]/).pop() : choice;
+                field.add(new Option(text, choice, false, choice === param.default));
+            });
+        } else {
+            field = document.createElement('input');
+            field.type = param.type === 'number' ? 'number' : 'text';
+            if (param.type === 'number') {
+                if (param.min !== undefined) field.min = param.min;
+                if (param.max !== undefined) field.max = param.max;
+                field.step = param.integer ? '1' : 'any';
+            }
+            field.value = param.default || '';
+        }
+        field.className = inputClass + " mt-1";
+        field.dataset.param = param.name;
+        if (param.required) field.required = true;
+
+        label.appendChild(field);
+        form.appendChild(label);
+    });
+    form.classList.remove('hidden');
+}
+
+function collectParameterValues() {
+    const fields = document.querySelectorAll('#modal-params [data-param]');
+    if (fields.length === 0) return null;
+
+    const values = {};
+    fields.forEach(field => {
+        if (field.value !== '') values[field.dataset.param] = field.value;
+    });
+    return values;
+}
+
+let logStream = null;
+
+function closeLogStream() {
+    if (logStream) {
+        logStream.close();
+        logStream = null;
+    }
+}
+
 function closeAppDetails() {
     const modal = document.getElementById('app-details-overlay');
     modal.classList.add('hidden');
+    document.getElementById('modal-logs').classList.add('hidden');
+    closeLogStream();
     currentlySelectedApp = null;
 }
 
+// Follow the live output of the running launch, appending to the log view
+function openLogStream(appId) {
+    closeLogStream();
+    const logsEl = document.getElementById('modal-logs');
+    logStream = new EventSource(`${API_BASE}/api/apps/${appId}/logs/stream`);
+
+    const append = (text) => {
+        const atBottom = logsEl.scrollTop + logsEl.clientHeight >= logsEl.scrollHeight - 4;
+        logsEl.innerText += (logsEl.innerText ? '\n' : '') + text;
+        if (atBottom) logsEl.scrollTop = logsEl.scrollHeight;
+    };
+
+    logStream.addEventListener('output', (e) => append(e.data));
+    logStream.addEventListener('dropped', (e) => append(`[... ${e.data} lines skipped ...]`));
+    logStream.addEventListener('exit', () => {
+        append('[process exited]');
+        closeLogStream();
+    });
+    // Not running or capture disabled: keep the stored tail only
+    logStream.onerror = () => closeLogStream();
+}
+
+async function toggleAppLogs() {
+    const logsEl = document.getElementById('modal-logs');
+    if (!logsEl.classList.contains('hidden')) {
+        logsEl.classList.add('hidden');
+        closeLogStream();
+        return;
+    }
+    if (!currentlySelectedApp) return;
+
+    logsEl.innerText = 'Loading...';
+    logsEl.classList.remove('hidden');
+    try {
+        const response = await fetch(`${API_BASE}/api/apps/${currentlySelectedApp.id}/logs?tail=200`);
+        if (!response.ok) {
+            logsEl.innerText = 'Failed to load logs.';
+            return;
+        }
+        const data = await response.json();
+        if (data.lines.length > 0) {
+            logsEl.innerText = data.lines.join('\n');
+        } else if (!data.capture_output) {
+            logsEl.innerText = 'Output capture is disabled for this app.';
+        } else {
+            logsEl.innerText = 'No output captured yet.';
+        }
+        logsEl.scrollTop = logsEl.scrollHeight;
+
+        if (data.capture_output && processStatuses[currentlySelectedApp.id]) {
+            openLogStream(currentlySelectedApp.id);
+        }
+    } catch (e) {
+        logsEl.innerText = 'Network error.';
+    }
+}
+
 function updateModalStatus() {
     if (!currentlySelectedApp) return;
 
     const led = document.getElementById('modal-status-led');
     const text = document.getElementById('modal-status-text');
     const badge = document.getElementById('modal-app-status-badge');
+    const stopBtn = document.getElementById('modal-stop-btn');
+    const restartBtn = document.getElementById('modal-restart-btn');
     const isRunning = processStatuses[currentlySelectedApp.id];
 
-    if (isRunning) {
+    stopBtn.classList.toggle('hidden', !isRunning);
+    restartBtn.classList.toggle('hidden', !isRunning);
+    fetchAppMetrics(currentlySelectedApp.id, isRunning);
+
+    if (appHealth(currentlySelectedApp.id) === 'unhealthy') {
+        led.className = 'w-2 h-2 rounded-full bg-amber-500 shadow-[0_0_8px_rgba(245,158,11,0.6)]';
+        text.innerText = 'Unhealthy';
+        text.className = 'text-xs font-semibold tracking-widest uppercase text-amber-400';
+        badge.className = 'inline-flex items-center gap-2 px-4 py-1.5 rounded-full bg-amber-500/10 border border-amber-500/20 mb-8';
+    } else if (isRunning) {
         led.className = 'w-2 h-2 rounded-full bg-green-500 animate-pulse shadow-[0_0_8px_rgba(16,185,129,0.6)]';
         text.innerText = 'Running';
         text.className = 'text-xs font-semibold tracking-widest uppercase text-green-400';
@@ -460,6 +752,53 @@ function updateModalStatus() {
     }
 }
 
+// Shows instances, uptime, CPU and memory of the selected app under its status badge,
+// or how its last launch ended when it is not running
+async function fetchAppMetrics(appId, isRunning) {
+    const metricsEl = document.getElementById('modal-app-metrics');
+
+    try {
+        const response = await fetch(`${API_BASE}/api/apps/${appId}/status`);
+        if (!response.ok) return;
+        const status = await response.json();
+        if (!currentlySelectedApp || currentlySelectedApp.id !== appId) return;
+
+        metricsEl.classList.remove('text-red-400');
+        if (isRunning && status.running) {
+            const instances = status.instances > 1 ? `${status.instances} instances · ` : '';
+            const restarts = status.restarts && status.restarts.total > 0 ? ` · restarted ${status.restarts.total}×` : '';
+            const failing = status.health === 'unhealthy' && status.health_check ? ` · ${status.health_check.error}` : '';
+            metricsEl.innerText = `${instances}up ${formatUptime(status.uptime_seconds)} · CPU ${status.cpu_percent.toFixed(1)}% · ${formatBytes(status.memory_bytes)}${restarts}${failing}`;
+        } else if (status.last_exit) {
+            const exit = status.last_exit;
+            const outcome = exit.abnormal ? `crashed (${exit.reason || 'exit code ' + exit.exit_code})` : (exit.stopped ? 'stopped' : 'exited');
+            const pending = status.restarts && status.restarts.next_restart_at ? ' · restarting soon' : '';
+            metricsEl.innerText = `Last run ${outcome} after ${formatUptime(exit.duration_seconds)}${pending}`;
+            metricsEl.classList.toggle('text-red-400', exit.abnormal);
+        } else {
+            metricsEl.classList.add('hidden');
+            return;
+        }
+        metricsEl.classList.remove('hidden');
+    } catch (e) {
+        console.error('Failed to fetch app status:', e);
+    }
+}
+
+function formatUptime(seconds) {
+    const s = Math.floor(seconds);
+    if (s < 60) return `${s}s`;
+    if (s < 3600) return `${Math.floor(s / 60)}m ${s % 60}s`;
+    if (s < 86400) return `${Math.floor(s / 3600)}h ${Math.floor(s % 3600 / 60)}m`;
+    return `${Math.floor(s / 86400)}d ${Math.floor(s % 86400 / 3600)}h`;
+}
+
+function formatBytes(bytes) {
+    if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(0)} KB`;
+    if (bytes < 1024 * 1024 * 1024) return `${(bytes / 1024 / 1024).toFixed(1)} MB`;
+    return `${(bytes / 1024 / 1024 / 1024).toFixed(2)} GB`;
+}
+
 function showToast(msg, duration = 2000) {
     toastMsg.innerText = msg;
     toastEl.classList.remove('opacity-0');
@@ -480,7 +819,7 @@ async function init() {
 
         if (isAuthorized) {
             await fetchApps();
-            startPolling();
+            startLiveUpdates();
         } else {
             grid.innerHTML = '<div class="col-span-full text-center py-20 text-slate-500">Authorization required. Please enter PIN.</div>';
         }
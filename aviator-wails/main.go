@@ -3,6 +3,7 @@ package main
 import (
 	"aviator-wails/internal/config"
 	"aviator-wails/internal/discovery"
+	"aviator-wails/internal/launcher"
 	"aviator-wails/internal/processmon"
 	"aviator-wails/internal/server"
 	"aviator-wails/internal/web"
@@ -63,7 +64,9 @@ func ensureSingleInstance() {
 func NewProcessMonitor(cm *config.ConfigManager) *processmon.ProcessMonitor {
 	pm := processmon.NewProcessMonitor()
 	for _, app := range cm.GetApps() {
-		pm.AddWatch(app.ID, app.Path)
+		if err := pm.AddWatch(app.ID, appWatch(app)); err != nil {
+			log.Printf("Warning: %v", err)
+		}
 	}
 	return pm
 }
@@ -80,6 +83,8 @@ func main() {
 		log.Fatalf("Failed to initialize config: %v", err)
 	}
 
+	launcher.SetLogDir(cm.LogDir)
+
 	// 2. Get Static Assets (For HTTP Server)
 	webFS, err := web.GetFS()
 	if err != nil {